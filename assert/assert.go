@@ -0,0 +1,293 @@
+// Package assert reports failed stub assertions to a testing.TB along with the
+// stub's call history. Each function returns whether the assertion held and
+// lets the test continue; package require stops the test instead.
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/brentburg/stubzero"
+)
+
+func Called(t testing.TB, s *stubzero.Stub) bool {
+	t.Helper()
+	if s.Called() {
+		return true
+	}
+	t.Errorf("expected %s to be called, but it was not called", s)
+	return false
+}
+
+func NotCalled(t testing.TB, s *stubzero.Stub) bool {
+	t.Helper()
+	if s.NotCalled() {
+		return true
+	}
+	t.Errorf("expected %s to not be called\n%s", s, history(s, nil, false, false))
+	return false
+}
+
+func CalledOnce(t testing.TB, s *stubzero.Stub) bool {
+	t.Helper()
+	return CallCount(t, s, 1)
+}
+
+func CallCount(t testing.TB, s *stubzero.Stub, n int) bool {
+	t.Helper()
+	if s.CallCount() == n {
+		return true
+	}
+	t.Errorf("expected %s to be called %s\n%s", s, stubzero.FormatTimes(n), history(s, nil, false, false))
+	return false
+}
+
+func CalledAtLeast(t testing.TB, s *stubzero.Stub, n int) bool {
+	t.Helper()
+	if s.CalledAtLeast(n) {
+		return true
+	}
+	t.Errorf("expected %s to be called at least %s\n%s", s, stubzero.FormatTimes(n), history(s, nil, false, false))
+	return false
+}
+
+func CalledAtMost(t testing.TB, s *stubzero.Stub, n int) bool {
+	t.Helper()
+	if s.CalledAtMost(n) {
+		return true
+	}
+	t.Errorf("expected %s to be called at most %s\n%s", s, stubzero.FormatTimes(n), history(s, nil, false, false))
+	return false
+}
+
+func CalledWithTimes(t testing.TB, s *stubzero.Stub, n int, args ...interface{}) bool {
+	t.Helper()
+	if s.CalledWithTimes(n, args...) {
+		return true
+	}
+	t.Errorf(
+		"expected %s to be called with %s %s, but it was called with them %s\n%s",
+		s, stubzero.FormatArgs(args), stubzero.FormatTimes(n), stubzero.FormatTimes(s.CallCountWith(args...)), history(s, args, false, true),
+	)
+	return false
+}
+
+func CalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.CalledWith(args...) {
+		return true
+	}
+	t.Errorf("expected %s to be called with %s\n%s", s, stubzero.FormatArgs(args), history(s, args, false, false))
+	return false
+}
+
+func CalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.CalledWithExactly(args...) {
+		return true
+	}
+	t.Errorf("expected %s to be called with exactly %s\n%s", s, stubzero.FormatArgs(args), history(s, args, true, false))
+	return false
+}
+
+func AlwaysCalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.AlwaysCalledWith(args...) {
+		return true
+	}
+	t.Errorf("expected %s to always be called with %s\n%s", s, stubzero.FormatArgs(args), history(s, args, false, false))
+	return false
+}
+
+func AlwaysCalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.AlwaysCalledWithExactly(args...) {
+		return true
+	}
+	t.Errorf("expected %s to always be called with exactly %s\n%s", s, stubzero.FormatArgs(args), history(s, args, true, false))
+	return false
+}
+
+func NeverCalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.NeverCalledWith(args...) {
+		return true
+	}
+	t.Errorf("expected %s to never be called with %s\n%s", s, stubzero.FormatArgs(args), history(s, args, false, true))
+	return false
+}
+
+func NeverCalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) bool {
+	t.Helper()
+	if s.NeverCalledWithExactly(args...) {
+		return true
+	}
+	t.Errorf("expected %s to never be called with exactly %s\n%s", s, stubzero.FormatArgs(args), history(s, args, true, true))
+	return false
+}
+
+func Returned(t testing.TB, s *stubzero.Stub, vals ...interface{}) bool {
+	t.Helper()
+	if s.Returned(vals...) {
+		return true
+	}
+	t.Errorf("expected %s to return %s\n%s", s, stubzero.FormatArgs(vals), results(s))
+	return false
+}
+
+func AlwaysReturned(t testing.TB, s *stubzero.Stub, vals ...interface{}) bool {
+	t.Helper()
+	if s.AlwaysReturned(vals...) {
+		return true
+	}
+	t.Errorf("expected %s to always return %s\n%s", s, stubzero.FormatArgs(vals), results(s))
+	return false
+}
+
+func Panicked(t testing.TB, s *stubzero.Stub) bool {
+	t.Helper()
+	if s.Panicked() {
+		return true
+	}
+	t.Errorf("expected %s to panic\n%s", s, results(s))
+	return false
+}
+
+func CalledBefore(t testing.TB, s, other *stubzero.Stub) bool {
+	t.Helper()
+	if s.CalledBefore(other) {
+		return true
+	}
+	t.Errorf("expected %s to be called before %s\n%s", s, otherName(other), histories(s, other))
+	return false
+}
+
+func CalledAfter(t testing.TB, s, other *stubzero.Stub) bool {
+	t.Helper()
+	if s.CalledAfter(other) {
+		return true
+	}
+	t.Errorf("expected %s to be called after %s\n%s", s, otherName(other), histories(s, other))
+	return false
+}
+
+func CalledImmediatelyBefore(t testing.TB, s, other *stubzero.Stub) bool {
+	t.Helper()
+	if s.CalledImmediatelyBefore(other) {
+		return true
+	}
+	t.Errorf("expected %s to be called immediately before %s\n%s", s, otherName(other), histories(s, other))
+	return false
+}
+
+func CalledImmediatelyAfter(t testing.TB, s, other *stubzero.Stub) bool {
+	t.Helper()
+	if s.CalledImmediatelyAfter(other) {
+		return true
+	}
+	t.Errorf("expected %s to be called immediately after %s\n%s", s, otherName(other), histories(s, other))
+	return false
+}
+
+func InOrder(t testing.TB, steps ...*stubzero.OrderStep) bool {
+	t.Helper()
+	if err := stubzero.InOrder(steps...); err != nil {
+		t.Error(err)
+		return false
+	}
+	return true
+}
+
+func InStrictOrder(t testing.TB, steps ...*stubzero.OrderStep) bool {
+	t.Helper()
+	if err := stubzero.InStrictOrder(steps...); err != nil {
+		t.Error(err)
+		return false
+	}
+	return true
+}
+
+// history lists the stub's calls. With args it adds the mismatched arguments
+// under each call that does not match them and marks the closest call, or
+// marks the calls that do match when matching is true.
+func history(s *stubzero.Stub, args []interface{}, exact, matching bool) string {
+	calls := s.Calls()
+	if len(calls) == 0 {
+		return s.String() + " was not called"
+	}
+	closest := 0
+	if args != nil && !matching {
+		if exact {
+			closest = s.ExplainCalledWithExactly(args...).N
+		} else {
+			closest = s.ExplainCalledWith(args...).N
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s was called %s:", s, stubzero.FormatTimes(len(calls)))
+	for i, c := range calls {
+		fmt.Fprintf(&b, "\n  call %d (seq %d): %s", i+1, c.Seq, stubzero.FormatArgs(c.Args))
+		if args == nil {
+			continue
+		}
+		mismatches := c.ExplainCalledWith(args...)
+		if exact {
+			mismatches = c.ExplainCalledWithExactly(args...)
+		}
+		if matching {
+			if len(mismatches) == 0 {
+				b.WriteString(" <- matches")
+			}
+			continue
+		}
+		if i+1 == closest {
+			b.WriteString(" <- closest")
+		}
+		for _, m := range mismatches {
+			for _, line := range strings.Split(m.String(), "\n") {
+				fmt.Fprintf(&b, "\n    %s", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// results lists the stub's calls with what each returned.
+func results(s *stubzero.Stub) string {
+	calls := s.Calls()
+	if len(calls) == 0 {
+		return s.String() + " was not called"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s was called %s:", s, stubzero.FormatTimes(len(calls)))
+	for i, c := range calls {
+		fmt.Fprintf(&b, "\n  call %d (seq %d): %s", i+1, c.Seq, stubzero.FormatArgs(c.Args))
+		switch r := c.Result(); {
+		case !r.Finished:
+			b.WriteString(" in flight")
+		case r.Panicked:
+			fmt.Fprintf(&b, " panicked with %#v", r.PanicValue)
+		default:
+			fmt.Fprintf(&b, " returned %s", stubzero.FormatArgs(r.ReturnValues))
+		}
+	}
+	return b.String()
+}
+
+// otherName names the second stub of an ordering assertion, calling it "other
+// stub" when it is unnamed.
+func otherName(other *stubzero.Stub) string {
+	if other.Name() == "" {
+		return "other stub"
+	}
+	return other.String()
+}
+
+func histories(s, other *stubzero.Stub) string {
+	h := history(other, nil, false, false)
+	if other.Name() == "" {
+		h = "other " + h
+	}
+	return history(s, nil, false, false) + "\n" + h
+}
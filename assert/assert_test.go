@@ -0,0 +1,361 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/brentburg/stubzero"
+	"github.com/brentburg/stubzero/match"
+)
+
+type recorder struct {
+	testing.TB
+	helpers int
+	output  []string
+}
+
+func (r *recorder) Helper() {
+	r.helpers++
+}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.output = append(r.output, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.output = append(r.output, fmt.Sprintf(format, args...))
+}
+
+func check(t *testing.T, name string, ok bool, r *recorder, want ...string) {
+	t.Helper()
+	if r.helpers == 0 {
+		t.Errorf("%s: expected t.Helper to be called", name)
+	}
+	if len(want) == 0 {
+		if !ok || len(r.output) > 0 {
+			t.Errorf("%s: expected to pass, got %v", name, r.output)
+		}
+		return
+	}
+	if ok || len(r.output) != 1 {
+		t.Fatalf("%s: expected one failure, got %v", name, r.output)
+	}
+	for _, w := range want {
+		if !strings.Contains(r.output[0], w) {
+			t.Errorf("%s: expected failure to contain %q:\n%s", name, w, r.output[0])
+		}
+	}
+}
+
+func TestCalled(t *testing.T) {
+	s := stubzero.New()
+	r := &recorder{}
+	check(t, "not called", Called(r, s), r, "expected stub to be called, but it was not called")
+	s.Call()
+	r = &recorder{}
+	check(t, "called", Called(r, s), r)
+}
+
+func TestNotCalled(t *testing.T) {
+	s := stubzero.New()
+	r := &recorder{}
+	check(t, "not called", NotCalled(r, s), r)
+	s.Call(1)
+	r = &recorder{}
+	check(t, "called", NotCalled(r, s), r, "expected stub to not be called", "call 1 (seq", "): (1)")
+}
+
+func TestCalledOnce(t *testing.T) {
+	s := stubzero.New()
+	s.Call()
+	r := &recorder{}
+	check(t, "called once", CalledOnce(r, s), r)
+	s.Call()
+	r = &recorder{}
+	check(t, "called twice", CalledOnce(r, s), r, "expected stub to be called 1 time", "stub was called 2 times:")
+}
+
+func TestCallCount(t *testing.T) {
+	s := stubzero.New()
+	r := &recorder{}
+	check(t, "not called", CallCount(r, s, 3), r, "expected stub to be called 3 times", "stub was not called")
+	s.Call("a")
+	s.Call("b")
+	s.Call("c")
+	r = &recorder{}
+	check(t, "called", CallCount(r, s, 3), r)
+}
+
+func TestCalledAtLeast(t *testing.T) {
+	s := stubzero.New()
+	s.Call()
+	r := &recorder{}
+	check(t, "called once", CalledAtLeast(r, s, 2), r, "expected stub to be called at least 2 times", "stub was called 1 time:")
+	s.Call()
+	r = &recorder{}
+	check(t, "called twice", CalledAtLeast(r, s, 2), r)
+}
+
+func TestCalledAtMost(t *testing.T) {
+	s := stubzero.New()
+	s.Call()
+	r := &recorder{}
+	check(t, "called once", CalledAtMost(r, s, 1), r)
+	s.Call()
+	r = &recorder{}
+	check(t, "called twice", CalledAtMost(r, s, 1), r, "expected stub to be called at most 1 time", "stub was called 2 times:")
+}
+
+func TestCalledWithTimes(t *testing.T) {
+	s := stubzero.New()
+	s.Call("a")
+	s.Call("b")
+	r := &recorder{}
+	check(t, "called with once", CalledWithTimes(r, s, 1, "a"), r)
+	r = &recorder{}
+	check(t, "called with twice", CalledWithTimes(r, s, 2, "a"), r,
+		`expected stub to be called with ("a") 2 times, but it was called with them 1 time`,
+		`): ("a") <- matches`,
+	)
+}
+
+func TestCalledWith(t *testing.T) {
+	s := stubzero.New()
+	s.Call(1, "b")
+	s.Call(2)
+	r := &recorder{}
+	check(t, "not called with", CalledWith(r, s, 1, "a"), r,
+		`expected stub to be called with (1, "a")`,
+		`call 1 (seq`, `): (1, "b") <- closest`,
+		`    arg 1: expected "a", got "b"`,
+		`): (2)`,
+		`    arg 0: expected 1, got 2`,
+		`    arg 1: expected "a", missing`,
+	)
+	r = &recorder{}
+	check(t, "called with", CalledWith(r, s, 1), r)
+	r = &recorder{}
+	check(t, "with matchers", CalledWith(r, s, match.Regexp("x")), r, `arg 0: match.Regexp("x") rejected 1`)
+}
+
+func TestCalledWithStructs(t *testing.T) {
+	type user struct {
+		Name  string
+		Roles []string
+	}
+	s := stubzero.New()
+	s.Call(user{"ann", []string{"admin", "dev"}})
+	r := &recorder{}
+	check(t, "not called with", CalledWith(r, s, user{"ann", []string{"admin", "ops"}}), r,
+		`arg 0.Roles[1]: expected "ops", got "dev"`,
+	)
+}
+
+func TestCalledWithExactly(t *testing.T) {
+	s := stubzero.New()
+	s.Call(1, 2)
+	r := &recorder{}
+	check(t, "not called with exactly", CalledWithExactly(r, s, 1), r,
+		"expected stub to be called with exactly (1)",
+		"arg 1: unexpected 2",
+	)
+	r = &recorder{}
+	check(t, "called with exactly", CalledWithExactly(r, s, 1, 2), r)
+}
+
+func TestAlwaysCalledWith(t *testing.T) {
+	s := stubzero.New()
+	s.Call(1, 2)
+	r := &recorder{}
+	check(t, "always called with", AlwaysCalledWith(r, s, 1), r)
+	s.Call(2)
+	r = &recorder{}
+	check(t, "not always called with", AlwaysCalledWith(r, s, 1), r,
+		"expected stub to always be called with (1)",
+		"arg 0: expected 1, got 2",
+	)
+}
+
+func TestAlwaysCalledWithExactly(t *testing.T) {
+	s := stubzero.New()
+	s.Call(1)
+	r := &recorder{}
+	check(t, "always called with exactly", AlwaysCalledWithExactly(r, s, 1), r)
+	s.Call(1, 2)
+	r = &recorder{}
+	check(t, "not always called with exactly", AlwaysCalledWithExactly(r, s, 1), r,
+		"expected stub to always be called with exactly (1)",
+		"arg 1: unexpected 2",
+	)
+}
+
+func TestNeverCalledWith(t *testing.T) {
+	s := stubzero.New()
+	s.Call(2)
+	r := &recorder{}
+	check(t, "never called with", NeverCalledWith(r, s, 1), r)
+	s.Call(1, 2)
+	r = &recorder{}
+	check(t, "called with", NeverCalledWith(r, s, 1), r,
+		"expected stub to never be called with (1)",
+		"): (1, 2) <- matches",
+	)
+}
+
+func TestNeverCalledWithExactly(t *testing.T) {
+	s := stubzero.New()
+	s.Call(1, 2)
+	r := &recorder{}
+	check(t, "never called with exactly", NeverCalledWithExactly(r, s, 1), r)
+	s.Call(1)
+	r = &recorder{}
+	check(t, "called with exactly", NeverCalledWithExactly(r, s, 1), r,
+		"expected stub to never be called with exactly (1)",
+		"): (1) <- matches",
+	)
+}
+
+func TestReturned(t *testing.T) {
+	s := stubzero.New()
+	s.ReturnsOnce(1, nil)
+	s.Call("a")
+	r := &recorder{}
+	check(t, "returned", Returned(r, s, 1, nil), r)
+	r = &recorder{}
+	check(t, "not returned", Returned(r, s, 2, nil), r,
+		"expected stub to return (2, <nil>)",
+		`call 1 (seq `, `): ("a") returned (1, <nil>)`,
+	)
+}
+
+func TestAlwaysReturned(t *testing.T) {
+	s := stubzero.New()
+	s.ReturnsOnce(1)
+	s.Returns(2)
+	s.Call()
+	r := &recorder{}
+	check(t, "always returned", AlwaysReturned(r, s, 1), r)
+	s.Call()
+	r = &recorder{}
+	check(t, "not always returned", AlwaysReturned(r, s, 1), r,
+		"expected stub to always return (1)",
+		"returned (1)", "returned (2)",
+	)
+}
+
+func TestPanicked(t *testing.T) {
+	s := stubzero.New()
+	s.Call()
+	r := &recorder{}
+	check(t, "not panicked", Panicked(r, s), r, "expected stub to panic", "returned ()")
+	s.Panics("oops")
+	func() {
+		defer func() { recover() }()
+		s.Call()
+	}()
+	r = &recorder{}
+	check(t, "panicked", Panicked(r, s), r)
+	r = &recorder{}
+	Returned(r, s, 1)
+	if !strings.Contains(r.output[0], `panicked with "oops"`) {
+		t.Errorf("expected history to show the panic:\n%s", r.output[0])
+	}
+}
+
+func TestCalledBefore(t *testing.T) {
+	s, other := stubzero.New(), stubzero.New()
+	other.Call()
+	s.Call()
+	r := &recorder{}
+	check(t, "called after", CalledBefore(r, s, other), r,
+		"expected stub to be called before other stub",
+		"stub was called 1 time",
+		"other stub was called 1 time",
+	)
+	r = &recorder{}
+	check(t, "called before", CalledBefore(r, other, s), r)
+}
+
+func TestNamedStubs(t *testing.T) {
+	s, other := stubzero.NewNamed("repo.Save"), stubzero.NewNamed("repo.Close")
+	other.Call()
+	s.Call(map[string]int{"id": 7})
+	r := &recorder{}
+	check(t, "named", CalledWith(r, s, match.Key("id", match.Regexp("x"))), r,
+		`expected repo.Save to be called with (match.Key("id", match.Regexp("x")))`,
+		"repo.Save was called 1 time",
+		`arg 0: match.Key("id", match.Regexp("x")) rejected map[string]int{"id":7}`,
+	)
+	r = &recorder{}
+	check(t, "named other", CalledBefore(r, s, other), r,
+		"expected repo.Save to be called before repo.Close",
+		"\nrepo.Close was called 1 time",
+	)
+	r = &recorder{}
+	check(t, "named not called", Called(r, stubzero.NewNamed("repo.Load")), r,
+		"expected repo.Load to be called, but it was not called",
+	)
+}
+
+func TestCalledAfter(t *testing.T) {
+	s, other := stubzero.New(), stubzero.New()
+	s.Call()
+	r := &recorder{}
+	check(t, "other not called", CalledAfter(r, s, other), r,
+		"expected stub to be called after other stub",
+		"other stub was not called",
+	)
+	other.Call()
+	r = &recorder{}
+	check(t, "called after", CalledAfter(r, other, s), r)
+}
+
+func TestCalledImmediatelyBefore(t *testing.T) {
+	s, other := stubzero.New(), stubzero.New()
+	s.Call()
+	other.Call()
+	r := &recorder{}
+	check(t, "called immediately before", CalledImmediatelyBefore(r, s, other), r)
+	r = &recorder{}
+	check(t, "called immediately after", CalledImmediatelyBefore(r, other, s), r,
+		"expected stub to be called immediately before other stub",
+	)
+}
+
+func TestCalledImmediatelyAfter(t *testing.T) {
+	s, other := stubzero.New(), stubzero.New()
+	s.Call()
+	other.Call()
+	r := &recorder{}
+	check(t, "called immediately after", CalledImmediatelyAfter(r, other, s), r)
+	r = &recorder{}
+	check(t, "called immediately before", CalledImmediatelyAfter(r, s, other), r,
+		"expected stub to be called immediately after other stub",
+	)
+}
+
+func TestInOrder(t *testing.T) {
+	open, closeStub := stubzero.New(), stubzero.New()
+	open.Call()
+	closeStub.Call()
+	r := &recorder{}
+	check(t, "in order", InOrder(r, stubzero.Called(open), stubzero.Called(closeStub)), r)
+	r = &recorder{}
+	check(t, "out of order", InOrder(r, stubzero.Called(closeStub), stubzero.Called(open)), r,
+		"step 2 expected stub #2 called with (), but it was called before step 1",
+	)
+}
+
+func TestInStrictOrder(t *testing.T) {
+	open, closeStub := stubzero.New(), stubzero.New()
+	open.Call()
+	open.Call()
+	closeStub.Call()
+	r := &recorder{}
+	check(t, "in order", InStrictOrder(r, stubzero.Called(open).AtLeastOnce(), stubzero.Called(closeStub)), r)
+	r = &recorder{}
+	check(t, "out of order", InStrictOrder(r, stubzero.Called(open), stubzero.Called(closeStub)), r,
+		"step 2 expected stub #2 called with (), got stub #1 called with ()",
+	)
+}
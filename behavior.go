@@ -0,0 +1,196 @@
+package stubzero
+
+import (
+	"container/list"
+	"time"
+)
+
+type Behavior struct {
+	stub          *Stub
+	args          []interface{}
+	callCount     int
+	onCall        map[int]*Behavior
+	returns       *list.List
+	defaultReturn []interface{}
+	defaultDoes   func(args ...interface{}) []interface{}
+	defaultAction string
+	defaultAfter  delay
+	maxCalls      int
+	limited       bool
+}
+
+type response struct {
+	action string
+	values []interface{}
+	does   func(args ...interface{}) []interface{}
+	after  delay
+}
+
+// delay holds a call for d before it returns, or until its context is done
+// if ctx is set.
+type delay struct {
+	d    time.Duration
+	ctx  bool
+	dist Distribution
+}
+
+func (r *response) respond(args []interface{}) []interface{} {
+	if r.does != nil {
+		return r.does(args...)
+	}
+	return r.values
+}
+
+func newBehavior(s *Stub, args ...interface{}) *Behavior {
+	b := &Behavior{
+		stub:    s,
+		args:    args,
+		onCall:  make(map[int]*Behavior),
+		returns: list.New(),
+	}
+	b.reset()
+	return b
+}
+
+func (b *Behavior) reset() {
+	b.callCount = 0
+	for _, ob := range b.onCall {
+		ob.reset()
+	}
+	b.returns.Init()
+	b.defaultReturn = make([]interface{}, 0)
+	b.defaultDoes = nil
+	b.defaultAction = ""
+	b.defaultAfter = delay{}
+	b.maxCalls = 0
+	b.limited = false
+}
+
+func (b *Behavior) ReturnsOnce(vals ...interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.returns.PushBack(&response{action: "ReturnsOnce", values: vals})
+}
+
+func (b *Behavior) Returns(vals ...interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.defaultReturn = vals
+	b.defaultDoes = nil
+	b.defaultAction = "Returns"
+	b.defaultAfter = delay{}
+}
+
+// ReturnsAfter sets values to return once the call has been held for d, as
+// measured by the stub's clock.
+func (b *Behavior) ReturnsAfter(d time.Duration, vals ...interface{}) {
+	b.returnsAfter("ReturnsAfter", delay{d: d}, vals)
+}
+
+// ReturnsAfterContext is like ReturnsAfter, but a call returns early with the
+// context's error if its context is done first, as described in the package
+// documentation.
+func (b *Behavior) ReturnsAfterContext(d time.Duration, vals ...interface{}) {
+	b.returnsAfter("ReturnsAfterContext", delay{d: d, ctx: true}, vals)
+}
+
+func (b *Behavior) returnsAfter(action string, after delay, vals []interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.defaultReturn = vals
+	b.defaultDoes = nil
+	b.defaultAction = action
+	b.defaultAfter = after
+}
+
+// DoesOnce queues fn to compute the result of a single call, in the same
+// queue as ReturnsOnce. fn runs after the call is recorded and without the
+// stub's lock held, so it may query the stub.
+func (b *Behavior) DoesOnce(fn func(args ...interface{}) []interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.returns.PushBack(&response{action: "DoesOnce", does: fn})
+}
+
+// Does sets fn to compute the result of calls, replacing any Returns values.
+func (b *Behavior) Does(fn func(args ...interface{}) []interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.defaultDoes = fn
+	b.defaultAction = "Does"
+	b.defaultAfter = delay{}
+}
+
+func (b *Behavior) PanicsOnce(v interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.returns.PushBack(&response{action: "PanicsOnce", does: panicWith(v)})
+}
+
+func (b *Behavior) Panics(v interface{}) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.defaultDoes = panicWith(v)
+	b.defaultAction = "Panics"
+	b.defaultAfter = delay{}
+}
+
+func panicWith(v interface{}) func(args ...interface{}) []interface{} {
+	return func(args ...interface{}) []interface{} {
+		panic(v)
+	}
+}
+
+// OnCall returns the behavior used for the nth call, counting from 1 like
+// Stub.NthCall. On the stub itself n is the absolute call number; on a
+// WithArgs behavior it counts only the calls matching those args. A configured
+// OnCall behavior takes precedence over ReturnsOnce and Returns values set on
+// the same behavior.
+func (b *Behavior) OnCall(n int) *Behavior {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	if ob, ok := b.onCall[n]; ok {
+		return ob
+	}
+	ob := newBehavior(b.stub)
+	b.onCall[n] = ob
+	return ob
+}
+
+func (b *Behavior) OnFirstCall() *Behavior {
+	return b.OnCall(1)
+}
+
+func (b *Behavior) OnSecondCall() *Behavior {
+	return b.OnCall(2)
+}
+
+func (b *Behavior) OnThirdCall() *Behavior {
+	return b.OnCall(3)
+}
+
+func (b *Behavior) responder() *Behavior {
+	if ob, ok := b.onCall[b.callCount]; ok && ob.responder() != nil {
+		return ob
+	}
+	if b.defaultAction != "" || b.returns.Len() > 0 {
+		return b
+	}
+	return nil
+}
+
+func (b *Behavior) matches(args []interface{}) bool {
+	return matchArgs(b.args, args)
+}
+
+func (b *Behavior) next() *response {
+	if b.returns.Len() > 0 {
+		return b.returns.Remove(b.returns.Front()).(*response)
+	}
+	return &response{
+		action: b.defaultAction,
+		values: b.defaultReturn,
+		does:   b.defaultDoes,
+		after:  b.defaultAfter,
+	}
+}
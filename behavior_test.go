@@ -0,0 +1,415 @@
+package stubzero
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/clock"
+	"github.com/brentburg/stubzero/match"
+)
+
+func TestStubWithArgs(t *testing.T) {
+	t.Run("with matching args", func(t *testing.T) {
+		s := New()
+		s.Returns(0)
+		s.WithArgs(1, 2).Returns(3)
+		if ret := s.Call(1, 2); ret[0].(int) != 3 {
+			t.Error("expected to return values from matching behavior")
+		}
+		if ret := s.Call(1, 2, 5); ret[0].(int) != 3 {
+			t.Error("expected to return values from behavior matching leading args")
+		}
+		if ret := s.Call(1); ret[0].(int) != 0 {
+			t.Error("expected to return default when args do not match")
+		}
+		if s.CallCount() != 3 {
+			t.Error("expected calls to be recorded on the stub")
+		}
+	})
+
+	t.Run("with matchers", func(t *testing.T) {
+		s := New()
+		s.WithArgs(match.Regexp("^a"), match.Any).Returns(true)
+		if ret := s.Call("abc", 1); len(ret) != 1 || !ret[0].(bool) {
+			t.Error("expected to return values from behavior with matching matchers")
+		}
+		if ret := s.Call("cba", 1); len(ret) != 0 {
+			t.Error("expected to return default when matchers do not match")
+		}
+	})
+
+	t.Run("with one time returns", func(t *testing.T) {
+		s := New()
+		s.Returns(0)
+		s.ReturnsOnce(-1)
+		s.WithArgs(1).ReturnsOnce(1)
+		s.WithArgs(1).ReturnsOnce(2)
+		if ret := s.Call(1); ret[0].(int) != 1 {
+			t.Error("expected to return one time values in order (first)")
+		}
+		if ret := s.Call(1); ret[0].(int) != 2 {
+			t.Error("expected to return one time values in order (second)")
+		}
+		if ret := s.Call(1); ret[0].(int) != -1 {
+			t.Error("expected to fall back to stub once behaviors are used up")
+		}
+		if ret := s.Call(1); ret[0].(int) != 0 {
+			t.Error("expected to fall back to stub default")
+		}
+	})
+
+	t.Run("with the same args", func(t *testing.T) {
+		s := New()
+		if s.WithArgs(1, []int{2}) != s.WithArgs(1, []int{2}) {
+			t.Error("expected deeply equal args to return the same behavior")
+		}
+		if s.WithArgs(1) == s.WithArgs(2) {
+			t.Error("expected different args to return different behaviors")
+		}
+	})
+
+	t.Run("with several matching behaviors", func(t *testing.T) {
+		s := New()
+		s.WithArgs(1, 2).Returns("specific")
+		s.WithArgs(1).Returns("general")
+		s.WithArgs(match.Any).Returns("any")
+		if ret := s.Call(1, 2); ret[0].(string) != "specific" {
+			t.Error("expected behavior with the most args to win")
+		}
+		if ret := s.Call(1); ret[0].(string) != "any" {
+			t.Error("expected most recently added behavior to win a tie")
+		}
+	})
+
+	t.Run("after reset", func(t *testing.T) {
+		s := New()
+		b := s.WithArgs(1)
+		b.Returns(1)
+		s.Reset()
+		if ret := s.Call(1); len(ret) != 0 {
+			t.Error("expected reset to clear behaviors")
+		}
+		b.Returns(2)
+		if ret := s.Call(1); ret[0].(int) != 2 {
+			t.Error("expected behavior to be usable after reset")
+		}
+	})
+}
+
+func TestStubOnCall(t *testing.T) {
+	t.Run("on the stub", func(t *testing.T) {
+		s := New()
+		s.Returns(0)
+		s.OnCall(3).Returns("third")
+		for i := 1; i <= 4; i++ {
+			ret := s.Call(i)
+			if i == 3 && ret[0] != "third" {
+				t.Error("expected 3rd call to return on call values")
+			}
+			if i != 3 && ret[0] != 0 {
+				t.Errorf("expected call %d to return default", i)
+			}
+		}
+	})
+
+	t.Run("with shorthands", func(t *testing.T) {
+		s := New()
+		s.OnFirstCall().Returns(1)
+		s.OnSecondCall().Returns(2)
+		s.OnThirdCall().Returns(3)
+		for i := 1; i <= 3; i++ {
+			if ret := s.Call(); ret[0].(int) != i {
+				t.Errorf("expected call %d to return %d", i, i)
+			}
+		}
+		if s.OnCall(2) != s.OnSecondCall() {
+			t.Error("expected the same call number to return the same behavior")
+		}
+	})
+
+	t.Run("with one time returns", func(t *testing.T) {
+		s := New()
+		s.ReturnsOnce("once")
+		s.OnFirstCall().Returns("first")
+		if ret := s.Call(); ret[0] != "first" {
+			t.Error("expected on call values to take precedence over one time values")
+		}
+		if ret := s.Call(); ret[0] != "once" {
+			t.Error("expected one time values to be kept for a later call")
+		}
+	})
+
+	t.Run("with args", func(t *testing.T) {
+		s := New()
+		s.Returns("default")
+		s.OnCall(2).Returns("stub second")
+		s.WithArgs("a").OnSecondCall().Returns("a second")
+		if ret := s.Call("a"); ret[0] != "default" {
+			t.Error("expected first call with args to return default")
+		}
+		if ret := s.Call("b"); ret[0] != "stub second" {
+			t.Error("expected second stub call to return on call values")
+		}
+		if ret := s.Call("a"); ret[0] != "a second" {
+			t.Error("expected second call with args to return on call values")
+		}
+	})
+
+	t.Run("after reset", func(t *testing.T) {
+		s := New()
+		s.OnFirstCall().Returns(1)
+		s.Call()
+		s.Reset()
+		if ret := s.Call(); len(ret) != 0 {
+			t.Error("expected reset to clear on call values")
+		}
+	})
+}
+
+func TestStubDoes(t *testing.T) {
+	t.Run("with args", func(t *testing.T) {
+		s := New()
+		s.Does(func(args ...interface{}) []interface{} {
+			return []interface{}{len(args[0].(string)), args[0]}
+		})
+		ret := s.Call("hello")
+		if ret[0].(int) != 5 || ret[1].(string) != "hello" {
+			t.Error("expected to return values computed from args")
+		}
+	})
+
+	t.Run("after the call is recorded", func(t *testing.T) {
+		s := New()
+		s.Does(func(args ...interface{}) []interface{} {
+			return []interface{}{s.CallCount()}
+		})
+		s.Call()
+		if ret := s.Call(); ret[0].(int) != 2 {
+			t.Error("expected function to see the current call recorded")
+		}
+	})
+
+	t.Run("with returns", func(t *testing.T) {
+		s := New()
+		s.Does(func(args ...interface{}) []interface{} {
+			return []interface{}{"does"}
+		})
+		s.Returns("returns")
+		if ret := s.Call(); ret[0] != "returns" {
+			t.Error("expected Returns to replace Does")
+		}
+		s.Does(func(args ...interface{}) []interface{} {
+			return []interface{}{"does"}
+		})
+		if ret := s.Call(); ret[0] != "does" {
+			t.Error("expected Does to replace Returns")
+		}
+	})
+}
+
+func TestStubDoesOnce(t *testing.T) {
+	s := New()
+	s.Returns("default")
+	s.ReturnsOnce("once")
+	s.DoesOnce(func(args ...interface{}) []interface{} {
+		return []interface{}{args[0]}
+	})
+	if ret := s.Call("a"); ret[0] != "once" {
+		t.Error("expected one time values in order (first)")
+	}
+	if ret := s.Call("b"); ret[0] != "b" {
+		t.Error("expected one time function in order (second)")
+	}
+	if ret := s.Call("c"); ret[0] != "default" {
+		t.Error("expected default after one time values are used up")
+	}
+}
+
+func TestCallBehavior(t *testing.T) {
+	s := New()
+	noop := func(args ...interface{}) []interface{} { return nil }
+	s.Call()
+	s.Returns(1)
+	s.Call()
+	s.DoesOnce(noop)
+	s.Call()
+	s.WithArgs(1).Does(noop)
+	s.Call(1)
+	s.OnCall(5).ReturnsOnce(2)
+	s.Call()
+
+	cases := []struct {
+		n        int
+		behavior *Behavior
+		action   string
+	}{
+		{1, nil, ""},
+		{2, &s.Behavior, "Returns"},
+		{3, &s.Behavior, "DoesOnce"},
+		{4, s.WithArgs(1), "Does"},
+		{5, s.OnCall(5), "ReturnsOnce"},
+	}
+	for _, c := range cases {
+		call := s.NthCall(c.n)
+		if call.Behavior != c.behavior || call.Action != c.action {
+			t.Errorf("expected call %d to be answered by %s", c.n, c.action)
+		}
+	}
+}
+
+func callAndRecover(s *Stub, args ...interface{}) (v interface{}) {
+	defer func() {
+		v = recover()
+	}()
+	s.Call(args...)
+	return nil
+}
+
+func TestStubPanics(t *testing.T) {
+	t.Run("on every call", func(t *testing.T) {
+		s := New()
+		s.Panics("boom")
+		for i := 0; i < 2; i++ {
+			if v := callAndRecover(s); v != "boom" {
+				t.Error("expected call to panic with value")
+			}
+		}
+		if s.CallCount() != 2 {
+			t.Error("expected calls to be recorded before panicking")
+		}
+		c := s.LastCall()
+		if !c.Panicked || c.PanicValue != "boom" || c.Action != "Panics" {
+			t.Error("expected call to be marked as panicked")
+		}
+	})
+
+	t.Run("with args", func(t *testing.T) {
+		s := New()
+		s.Returns(1)
+		s.WithArgs("bad").Panics("boom")
+		if v := callAndRecover(s, "good"); v != nil {
+			t.Error("expected call to not panic when args do not match")
+		}
+		if v := callAndRecover(s, "bad"); v != "boom" {
+			t.Error("expected call to panic when args match")
+		}
+		if s.FirstCall().Panicked || !s.LastCall().Panicked {
+			t.Error("expected only the matching call to be marked as panicked")
+		}
+	})
+
+	t.Run("from a function", func(t *testing.T) {
+		s := New()
+		s.Does(func(args ...interface{}) []interface{} {
+			panic("from does")
+		})
+		if v := callAndRecover(s); v != "from does" {
+			t.Error("expected call to panic with value")
+		}
+		if c := s.LastCall(); !c.Panicked || c.PanicValue != "from does" {
+			t.Error("expected call to be marked as panicked")
+		}
+	})
+
+	t.Run("not on runtime.Goexit", func(t *testing.T) {
+		s := New()
+		s.Does(func(args ...interface{}) []interface{} {
+			runtime.Goexit()
+			return nil
+		})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.Call()
+		}()
+		<-done
+		if c := s.LastCall(); c.Panicked || !c.Result().Finished {
+			t.Errorf("expected call to be finished without panicking, got %+v", c)
+		}
+	})
+}
+
+func TestStubPanicsOnce(t *testing.T) {
+	s := New()
+	s.Returns(1)
+	s.OnCall(3).PanicsOnce("third")
+	s.PanicsOnce("first")
+	if v := callAndRecover(s); v != "first" {
+		t.Error("expected first call to panic")
+	}
+	if v := callAndRecover(s); v != nil {
+		t.Error("expected second call to not panic")
+	}
+	if v := callAndRecover(s); v != "third" {
+		t.Error("expected third call to panic")
+	}
+	if !s.NthCall(1).Panicked || s.NthCall(2).Panicked || !s.NthCall(3).Panicked {
+		t.Error("expected only panicking calls to be marked as panicked")
+	}
+}
+
+func TestStubReturnsAfter(t *testing.T) {
+	fake := clock.NewFake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := New(WithClock(fake))
+	s.ReturnsAfter(time.Second, 1)
+	s.WithArgs("fast").Returns(2)
+	results := make(chan []interface{})
+	go func() { results <- s.Call() }()
+	fake.BlockUntil(1)
+	select {
+	case <-results:
+		t.Fatal("expected call to wait for the clock")
+	default:
+	}
+	fake.Advance(time.Second)
+	if vals := <-results; len(vals) != 1 || vals[0] != 1 {
+		t.Errorf("expected [1], got %v", vals)
+	}
+	c := s.LastCall()
+	if c.Duration != time.Second || c.Action != "ReturnsAfter" {
+		t.Errorf("expected call held for 1s by ReturnsAfter, got %s by %s", c.Duration, c.Action)
+	}
+	if vals := s.Call("fast"); len(vals) != 1 || vals[0] != 2 {
+		t.Errorf("expected matching behavior to return [2] at once, got %v", vals)
+	}
+
+	s.Returns(3)
+	if vals := s.Call(); len(vals) != 1 || vals[0] != 3 {
+		t.Errorf("expected Returns to replace the delay, got %v", vals)
+	}
+	if fake.Waiters() != 0 {
+		t.Errorf("expected no timers left, got %d", fake.Waiters())
+	}
+}
+
+func TestStubReturnsAfterContext(t *testing.T) {
+	fake := clock.NewFake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := New(WithClock(fake))
+	s.ReturnsAfterContext(time.Second, "value", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan []interface{})
+	go func() { results <- s.Call(ctx) }()
+	fake.BlockUntil(1)
+	cancel()
+	vals := <-results
+	if len(vals) != 2 || vals[0] != "value" || vals[1] != context.Canceled {
+		t.Errorf("expected [value, context.Canceled], got %v", vals)
+	}
+	if d := s.LastCall().Duration; d != 0 {
+		t.Errorf("expected no fake time to pass, got %s", d)
+	}
+
+	go func() { results <- s.Call(context.Background()) }()
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	if vals := <-results; len(vals) != 2 || vals[1] != nil {
+		t.Errorf("expected [value, nil], got %v", vals)
+	}
+
+	s.ReturnsAfterContext(time.Second)
+	if vals := s.Call(ctx); len(vals) != 1 || vals[0] != context.Canceled {
+		t.Errorf("expected [context.Canceled], got %v", vals)
+	}
+}
@@ -1,30 +1,71 @@
 package stubzero
 
 import (
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/brentburg/stubzero/match"
 )
 
+// Seq is a process-wide sequence number that orders calls across all stubs;
+// Time, from the stub's clock, is informational only, as clocks may give
+// calls the same time.
+//
+// Behavior and Action record what produced the call's result: the behavior
+// that answered it and the method used to configure it, such as "Returns" or
+// "DoesOnce". Both are empty when the stub had nothing configured, and Action
+// is "Strict" when a strict stub panicked instead of answering.
+// ReturnValues, Panicked, PanicValue and Duration are set once producing the
+// result has finished or panicked; Duration is measured by the stub's clock
+// and includes any time the call was blocked. Read them directly only once
+// the call has returned; use Result or Returned for calls that may still be
+// in flight.
 type Call struct {
-	Args []interface{}
-	Time time.Time
+	Args         []interface{}
+	Seq          uint64
+	Time         time.Time
+	Behavior     *Behavior
+	Action       string
+	ReturnValues []interface{}
+	Panicked     bool
+	PanicValue   interface{}
+	Duration     time.Duration
+	finished     bool
+	stub         *Stub
+	index        int
 }
 
+// CallResult is a snapshot of a call's result fields, taken by Call.Result.
+type CallResult struct {
+	ReturnValues []interface{}
+	Panicked     bool
+	PanicValue   interface{}
+	Duration     time.Duration
+	Finished     bool
+}
+
+var callSeq uint64
+
 func newCall(args ...interface{}) *Call {
 	return &Call{
 		Args: args,
-		Time: time.Now(),
+		Seq:  atomic.AddUint64(&callSeq, 1),
 	}
 }
 
-func (c *Call) CalledWith(args ...interface{}) bool {
-	for i, arg := range args {
-		if !match.Match(arg, c.Args[i]) {
-			return false
-		}
+// String renders the call as its stub's name, its number counting from 1 and
+// its args, such as `repo.Save call 2: ("a", 1)`.
+func (c *Call) String() string {
+	name := "stub"
+	if c.stub != nil {
+		name = c.stub.String()
 	}
-	return true
+	return fmt.Sprintf("%s call %d: %s", name, c.index, FormatArgs(c.Args))
+}
+
+func (c *Call) CalledWith(args ...interface{}) bool {
+	return matchArgs(args, c.Args)
 }
 
 func (c *Call) CalledWithExactly(args ...interface{}) bool {
@@ -34,10 +75,69 @@ func (c *Call) CalledWithExactly(args ...interface{}) bool {
 	return c.CalledWith(args...)
 }
 
+// Result returns the call's result fields, synchronized with the stub
+// finishing the call.
+func (c *Call) Result() CallResult {
+	defer c.lock()()
+	return CallResult{
+		ReturnValues: c.ReturnValues,
+		Panicked:     c.Panicked,
+		PanicValue:   c.PanicValue,
+		Duration:     c.Duration,
+		Finished:     c.finished,
+	}
+}
+
+// Returned reports whether the call has returned values matching vals
+// exactly, compared with match.Match.
+func (c *Call) Returned(vals ...interface{}) bool {
+	defer c.lock()()
+	return c.returned(vals)
+}
+
+// lock locks the stub that recorded c, which guards its result fields, and
+// returns the function that unlocks it.
+func (c *Call) lock() func() {
+	if c.stub == nil {
+		return func() {}
+	}
+	c.stub.mu.Lock()
+	return c.stub.mu.Unlock
+}
+
+func (c *Call) returned(vals []interface{}) bool {
+	if !c.finished || c.Panicked || len(vals) != len(c.ReturnValues) {
+		return false
+	}
+	return matchArgs(vals, c.ReturnValues)
+}
+
 func (c *Call) CalledBefore(d *Call) bool {
-	return c.Time.Before(d.Time)
+	return c.Seq < d.Seq
 }
 
 func (c *Call) CalledAfter(d *Call) bool {
-	return c.Time.After(d.Time)
+	return c.Seq > d.Seq
+}
+
+// CalledImmediatelyBefore reports whether no other call on any stub was made
+// between c and d.
+func (c *Call) CalledImmediatelyBefore(d *Call) bool {
+	return c.Seq+1 == d.Seq
+}
+
+func (c *Call) CalledImmediatelyAfter(d *Call) bool {
+	return d.CalledImmediatelyBefore(c)
+}
+
+func matchArgs(expected, actual []interface{}) bool {
+	if len(expected) > len(actual) {
+		return false
+	}
+	for i, arg := range expected {
+		if !match.Match(arg, actual[i]) {
+			return false
+		}
+	}
+	return true
 }
@@ -1,7 +1,12 @@
 package stubzero
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/brentburg/stubzero/match"
 )
@@ -36,6 +41,13 @@ func TestCallCalledWith(t *testing.T) {
 			t.Error("expected false when calling with invalid matcher")
 		}
 	})
+
+	t.Run("with more values than args", func(t *testing.T) {
+		c := newCall(1)
+		if c.CalledWith(1, 2) {
+			t.Error("expected false when calling with more values than args")
+		}
+	})
 }
 
 func TestCallCalledWithExactly(t *testing.T) {
@@ -48,6 +60,82 @@ func TestCallCalledWithExactly(t *testing.T) {
 	}
 }
 
+func TestCallReturned(t *testing.T) {
+	s := New()
+	s.ReturnsOnce(1, errors.New("boom"))
+	s.PanicsOnce("oops")
+	s.Call()
+	func() {
+		defer func() { recover() }()
+		s.Call()
+	}()
+	c := s.FirstCall()
+	if !c.Returned(1, match.Any) {
+		t.Error("expected call to have returned 1, any")
+	}
+	if c.Returned(1) {
+		t.Error("expected false when matching fewer values than returned")
+	}
+	if c.Returned(2, match.Any) {
+		t.Error("expected false when matching different values")
+	}
+	if s.NthCall(2).Returned() {
+		t.Error("expected false for a call that panicked")
+	}
+	if c := newCall(); c.Returned() {
+		t.Error("expected false for a call that has not finished")
+	}
+}
+
+func TestCallResult(t *testing.T) {
+	s := New()
+	s.Returns(1)
+	g := s.Blocks()
+	go s.Call("x")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := s.WaitForCallWith(ctx, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := c.Result(); r.Finished || c.Returned(1) {
+		t.Errorf("expected a blocked call to have no result, got %+v", r)
+	}
+	g.ReleaseAll()
+	for !c.Returned(1) {
+		if ctx.Err() != nil {
+			t.Fatal("expected released call to return 1")
+		}
+		runtime.Gosched()
+	}
+	r := c.Result()
+	if !r.Finished || r.Panicked || !reflect.DeepEqual(r.ReturnValues, []interface{}{1}) {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
+
+func TestCallString(t *testing.T) {
+	s := NewNamed("repo.Save")
+	s.Call("a", 1)
+	s.Call([]string{"b"}, nil)
+	if got := s.FirstCall().String(); got != `repo.Save call 1: ("a", 1)` {
+		t.Errorf("unexpected string %s", got)
+	}
+	if got := s.LastCall().String(); got != `repo.Save call 2: ([]string{"b"}, <nil>)` {
+		t.Errorf("unexpected string %s", got)
+	}
+	s.ResetHistory()
+	s.Call()
+	if got := s.LastCall().String(); got != "repo.Save call 1: ()" {
+		t.Errorf("expected call numbers to restart after ResetHistory, got %s", got)
+	}
+	u := New()
+	u.Call(match.Any)
+	if got := u.LastCall().String(); got != "stub call 1: (match.Any)" {
+		t.Errorf("unexpected string %s", got)
+	}
+}
+
 func TestCallCalledBefore(t *testing.T) {
 	first := newCall()
 	second := newCall()
@@ -69,3 +157,46 @@ func TestCallCalledAfter(t *testing.T) {
 		t.Error("expected first to not be called after second")
 	}
 }
+
+func TestCallSeq(t *testing.T) {
+	first := newCall()
+	second := newCall()
+	if second.Seq <= first.Seq {
+		t.Error("expected sequence numbers to increase")
+	}
+	now := time.Now()
+	first.Time, second.Time = now, now
+	if !first.CalledBefore(second) || !second.CalledAfter(first) {
+		t.Error("expected ordering to use sequence numbers when times are equal")
+	}
+}
+
+func TestCallCalledImmediatelyBefore(t *testing.T) {
+	first := newCall()
+	second := newCall()
+	third := newCall()
+	if !first.CalledImmediatelyBefore(second) {
+		t.Error("expected first to be called immediately before second")
+	}
+	if first.CalledImmediatelyBefore(third) {
+		t.Error("expected first to not be called immediately before third")
+	}
+	if second.CalledImmediatelyBefore(first) {
+		t.Error("expected second to not be called immediately before first")
+	}
+}
+
+func TestCallCalledImmediatelyAfter(t *testing.T) {
+	first := newCall()
+	second := newCall()
+	third := newCall()
+	if !second.CalledImmediatelyAfter(first) {
+		t.Error("expected second to be called immediately after first")
+	}
+	if third.CalledImmediatelyAfter(first) {
+		t.Error("expected third to not be called immediately after first")
+	}
+	if first.CalledImmediatelyAfter(second) {
+		t.Error("expected first to not be called immediately after second")
+	}
+}
@@ -0,0 +1,67 @@
+package stubzero
+
+// Calls is a snapshot of recorded calls, in the order they were made, with
+// helpers for querying them.
+type Calls []*Call
+
+func (s *Stub) Calls() Calls {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make(Calls, len(s.calls))
+	copy(calls, s.calls)
+	return calls
+}
+
+// Where returns the calls whose leading arguments match args, compared with
+// match.Match as in CalledWith.
+func (cs Calls) Where(args ...interface{}) Calls {
+	return cs.Filter(func(c *Call) bool {
+		return c.CalledWith(args...)
+	})
+}
+
+func (cs Calls) WhereExactly(args ...interface{}) Calls {
+	return cs.Filter(func(c *Call) bool {
+		return c.CalledWithExactly(args...)
+	})
+}
+
+func (cs Calls) Filter(fn func(*Call) bool) Calls {
+	filtered := make(Calls, 0, len(cs))
+	for _, c := range cs {
+		if fn(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// Args returns the ith argument, counting from 0, of each call. Calls made
+// with fewer arguments contribute nil, as do all calls for a negative i.
+func (cs Calls) Args(i int) []interface{} {
+	args := make([]interface{}, len(cs))
+	for j, c := range cs {
+		if i >= 0 && i < len(c.Args) {
+			args[j] = c.Args[i]
+		}
+	}
+	return args
+}
+
+func (cs Calls) Count() int {
+	return len(cs)
+}
+
+func (cs Calls) First() *Call {
+	if len(cs) == 0 {
+		return nil
+	}
+	return cs[0]
+}
+
+func (cs Calls) Last() *Call {
+	if len(cs) == 0 {
+		return nil
+	}
+	return cs[len(cs)-1]
+}
@@ -0,0 +1,114 @@
+package stubzero
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+func TestStubCalls(t *testing.T) {
+	s := New()
+	if calls := s.Calls(); calls == nil || len(calls) != 0 {
+		t.Error("expected an empty snapshot when not called")
+	}
+	s.Call(1)
+	s.Call(2)
+	calls := s.Calls()
+	s.Call(3)
+	if len(calls) != 2 || calls[0] != s.FirstCall() || calls[1] != s.NthCall(2) {
+		t.Error("expected a snapshot of the calls made so far")
+	}
+}
+
+func TestCallsWhere(t *testing.T) {
+	s := New()
+	s.Call(map[string]int{"id": 7}, "a")
+	s.Call(map[string]int{"id": 8}, "b")
+	s.Call(map[string]int{"id": 7}, "c")
+	calls := s.Calls().Where(match.Key("id", 7))
+	if len(calls) != 2 || calls[0] != s.NthCall(1) || calls[1] != s.NthCall(3) {
+		t.Error("expected calls with matching args")
+	}
+	if len(s.Calls().Where(match.Any, "b")) != 1 {
+		t.Error("expected calls with matching values")
+	}
+	if len(s.Calls().Where(match.Any, "b", 1)) != 0 {
+		t.Error("expected no calls when more args are given than made")
+	}
+}
+
+func TestCallsWhereExactly(t *testing.T) {
+	s := New()
+	s.Call(1)
+	s.Call(1, 2)
+	if calls := s.Calls().WhereExactly(1); len(calls) != 1 || calls[0] != s.FirstCall() {
+		t.Error("expected calls with exactly matching args")
+	}
+}
+
+func TestCallsFilter(t *testing.T) {
+	s := New()
+	s.Call(1)
+	s.Call(1, 2)
+	s.Call(1, 2, 3)
+	calls := s.Calls().Filter(func(c *Call) bool { return len(c.Args) > 1 })
+	if len(calls) != 2 || calls[0] != s.NthCall(2) {
+		t.Error("expected calls accepted by the function")
+	}
+}
+
+func TestCallsArgs(t *testing.T) {
+	s := New()
+	s.Call("a", 1)
+	s.Call("b")
+	s.Call("c", 3)
+	if args := s.Calls().Args(0); !reflect.DeepEqual(args, []interface{}{"a", "b", "c"}) {
+		t.Errorf("expected first args of each call, got %v", args)
+	}
+	if args := s.Calls().Args(1); !reflect.DeepEqual(args, []interface{}{1, nil, 3}) {
+		t.Errorf("expected nil for calls without the arg, got %v", args)
+	}
+	if args := s.Calls().Where("c").Args(1); !reflect.DeepEqual(args, []interface{}{3}) {
+		t.Errorf("expected args of filtered calls, got %v", args)
+	}
+	if args := s.Calls().Args(-1); !reflect.DeepEqual(args, []interface{}{nil, nil, nil}) {
+		t.Errorf("expected nil for a negative index, got %v", args)
+	}
+}
+
+func TestCallsCount(t *testing.T) {
+	s := New()
+	s.Call(1)
+	s.Call(2)
+	s.Call(1)
+	if s.Calls().Count() != 3 || s.Calls().Where(1).Count() != 2 {
+		t.Error("expected count of calls")
+	}
+}
+
+func TestCallsFirst(t *testing.T) {
+	s := New()
+	if s.Calls().First() != nil {
+		t.Error("expected first to be nil when there are no calls")
+	}
+	s.Call(1)
+	s.Call(2)
+	s.Call(2)
+	if s.Calls().Where(2).First() != s.NthCall(2) {
+		t.Error("expected first matching call")
+	}
+}
+
+func TestCallsLast(t *testing.T) {
+	s := New()
+	if s.Calls().Last() != nil {
+		t.Error("expected last to be nil when there are no calls")
+	}
+	s.Call(1)
+	s.Call(1)
+	s.Call(2)
+	if s.Calls().Where(1).Last() != s.NthCall(2) {
+		t.Error("expected last matching call")
+	}
+}
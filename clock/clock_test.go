@@ -0,0 +1,27 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReal(t *testing.T) {
+	c := Real()
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+	if d := c.Since(start); d < time.Millisecond {
+		t.Errorf("expected at least 1ms to pass, got %s", d)
+	}
+	select {
+	case <-c.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Error("expected After to fire")
+	}
+	timer := c.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Error("expected Stop to report an active timer")
+	}
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+}
@@ -0,0 +1,132 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeNow(t *testing.T) {
+	f := NewFake(epoch)
+	if !f.Now().Equal(epoch) {
+		t.Errorf("expected %s, got %s", epoch, f.Now())
+	}
+	f.Advance(time.Minute)
+	if d := f.Since(epoch); d != time.Minute {
+		t.Errorf("expected 1m since epoch, got %s", d)
+	}
+	f.Set(epoch)
+	if !f.Now().Equal(epoch) {
+		t.Errorf("expected Set to move the clock back, got %s", f.Now())
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Second)
+	f.Advance(999 * time.Millisecond)
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("expected timer to not fire early")
+	}
+	f.Advance(time.Millisecond)
+	if at, ok := fired(timer.C()); !ok || !at.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("expected timer to fire at 1s, got %s, %v", at, ok)
+	}
+	if timer.Stop() {
+		t.Error("expected Stop to report a fired timer")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("expected Reset to report a fired timer")
+	}
+	if !timer.Stop() {
+		t.Error("expected Stop to report a reset timer")
+	}
+	f.Advance(time.Hour)
+	if _, ok := fired(timer.C()); ok {
+		t.Error("expected stopped timer to not fire")
+	}
+	if f.Waiters() != 0 {
+		t.Errorf("expected no waiters, got %d", f.Waiters())
+	}
+
+	if _, ok := fired(f.NewTimer(0).C()); !ok {
+		t.Error("expected timer with no duration to fire immediately")
+	}
+}
+
+func TestFakeTimersFireInOrder(t *testing.T) {
+	f := NewFake(epoch)
+	var order []int
+	late, early := f.NewTimer(2*time.Second), f.NewTimer(time.Second)
+	f.Set(epoch.Add(time.Minute))
+	for i, c := range []<-chan time.Time{early.C(), late.C()} {
+		if at, ok := fired(c); ok {
+			order = append(order, int(at.Sub(epoch)/time.Second))
+		} else {
+			t.Errorf("expected timer %d to fire", i)
+		}
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("expected timers to fire at 1s and 2s, got %v", order)
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Second)
+	for i := 1; i <= 3; i++ {
+		f.Advance(time.Second)
+		if at, ok := fired(ticker.C()); !ok || !at.Equal(epoch.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("expected tick %d, got %s, %v", i, at, ok)
+		}
+	}
+	f.Advance(5 * time.Second)
+	if _, ok := fired(ticker.C()); !ok {
+		t.Error("expected a tick after advancing past several")
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("expected missed ticks to be dropped")
+	}
+	ticker.Reset(time.Minute)
+	f.Advance(time.Second)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("expected reset ticker to wait for the new interval")
+	}
+	ticker.Stop()
+	f.Advance(time.Hour)
+	if _, ok := fired(ticker.C()); ok {
+		t.Error("expected stopped ticker to not tick")
+	}
+}
+
+func TestFakeSleep(t *testing.T) {
+	f := NewFake(epoch)
+	done := make(chan struct{})
+	go func() {
+		f.Sleep(time.Second)
+		close(done)
+	}()
+	f.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatal("expected Sleep to wait for the clock")
+	default:
+	}
+	f.Advance(time.Second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Sleep to return after advancing")
+	}
+}
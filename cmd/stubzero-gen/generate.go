@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const stubzeroPath = "github.com/brentburg/stubzero"
+
+type config struct {
+	Package       string
+	Interface     string
+	Name          string
+	OutputPackage string
+}
+
+type generator struct {
+	pkg     *types.Package
+	local   bool
+	imports map[string]string
+	names   map[string]string
+	tparams []string
+}
+
+func generate(cfg config) ([]byte, error) {
+	pkg, err := load(cfg.Package)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := pkg.Scope().Lookup(cfg.Interface).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%s: no type %s", pkg.Path(), cfg.Interface)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not an interface", pkg.Path(), cfg.Interface)
+	}
+	if !iface.IsMethodSet() {
+		return nil, fmt.Errorf("%s.%s is a type constraint", pkg.Path(), cfg.Interface)
+	}
+	if cfg.Name == "" {
+		cfg.Name = "Fake" + cfg.Interface
+	}
+	if cfg.OutputPackage == "" {
+		cfg.OutputPackage = pkg.Name()
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		local:   cfg.OutputPackage == pkg.Name(),
+		imports: make(map[string]string),
+		names:   make(map[string]string),
+	}
+	g.importName(stubzeroPath, "stubzero")
+
+	methods := make([]*types.Func, iface.NumMethods())
+	for i := range methods {
+		m := iface.Method(i)
+		if !m.Exported() && !g.local {
+			return nil, fmt.Errorf("%s.%s has unexported method %s", pkg.Path(), cfg.Interface, m.Name())
+		}
+		methods[i] = m
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name() < methods[j].Name()
+	})
+
+	tparams, targs := g.typeParams(obj.Type())
+	fake := cfg.Name + targs
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s is a fake of %s that records calls to each method on a stub.\n", cfg.Name, cfg.Interface)
+	fmt.Fprintf(&body, "type %s%s struct {\n", cfg.Name, tparams)
+	for _, m := range methods {
+		fmt.Fprintf(&body, "%sStub *stubzero.Stub\n", m.Name())
+	}
+	fmt.Fprintf(&body, "}\n\n")
+	fmt.Fprintf(&body, "func New%s%s() *%s {\n", cfg.Name, tparams, fake)
+	fmt.Fprintf(&body, "return &%s{\n", fake)
+	for _, m := range methods {
+		fmt.Fprintf(&body, "%sStub: stubzero.NewNamed(%q),\n", m.Name(), cfg.Interface+"."+m.Name())
+	}
+	fmt.Fprintf(&body, "}\n}\n")
+	if tparams == "" {
+		fmt.Fprintf(&body, "\nvar _ %s = (*%s)(nil)\n", types.TypeString(obj.Type(), g.qualifier), fake)
+	}
+	for _, m := range methods {
+		body.WriteString("\n")
+		g.method(&body, fake, m)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by stubzero-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", cfg.OutputPackage)
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	fmt.Fprintf(&buf, "import (\n")
+	for i, paths := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 {
+			buf.WriteString("\n")
+		}
+		for _, path := range paths {
+			if name := g.imports[path]; name != filepath.Base(path) {
+				fmt.Fprintf(&buf, "%s ", name)
+			}
+			fmt.Fprintf(&buf, "%q\n", path)
+		}
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+func load(path string) (*types.Package, error) {
+	bp, err := build.Import(path, ".", 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(importPath(bp), fset, files, nil)
+}
+
+// importPath resolves the import path of a package given as a directory,
+// which go/build leaves as the relative path in module mode.
+func importPath(bp *build.Package) string {
+	if !build.IsLocalImport(bp.ImportPath) {
+		return bp.ImportPath
+	}
+	dir, err := filepath.Abs(bp.Dir)
+	if err != nil {
+		return bp.ImportPath
+	}
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", dir).Output()
+	if err != nil {
+		return bp.ImportPath
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (g *generator) qualifier(p *types.Package) string {
+	if g.local && p.Path() == g.pkg.Path() {
+		return ""
+	}
+	return g.importName(p.Path(), p.Name())
+}
+
+func (g *generator) importName(path, name string) string {
+	if n, ok := g.imports[path]; ok {
+		return n
+	}
+	n := name
+	for i := 2; g.names[n] != ""; i++ {
+		n = fmt.Sprintf("%s%d", name, i)
+	}
+	g.imports[path] = n
+	g.names[n] = path
+	return n
+}
+
+func (g *generator) typeParams(t types.Type) (string, string) {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeParams().Len() == 0 {
+		return "", ""
+	}
+	tps := named.TypeParams()
+	decls := make([]string, tps.Len())
+	names := make([]string, tps.Len())
+	for i := range decls {
+		tp := tps.At(i)
+		names[i] = tp.Obj().Name()
+		g.tparams = append(g.tparams, names[i])
+		decls[i] = names[i] + " " + types.TypeString(tp.Constraint(), g.qualifier)
+	}
+	return "[" + strings.Join(decls, ", ") + "]", "[" + strings.Join(names, ", ") + "]"
+}
+
+func (g *generator) method(w *bytes.Buffer, fake string, m *types.Func) {
+	sig := m.Type().(*types.Signature)
+	params, results := sig.Params(), sig.Results()
+
+	ptypes := make([]string, params.Len())
+	for i := range ptypes {
+		t := params.At(i).Type()
+		if sig.Variadic() && i == params.Len()-1 {
+			ptypes[i] = "..." + types.TypeString(t.(*types.Slice).Elem(), g.qualifier)
+			continue
+		}
+		ptypes[i] = types.TypeString(t, g.qualifier)
+	}
+	rtypes := make([]string, results.Len())
+	for i := range rtypes {
+		rtypes[i] = types.TypeString(results.At(i).Type(), g.qualifier)
+	}
+
+	used := map[string]bool{"fake": true, "ret": true, "callArgs": true}
+	for name := range g.names {
+		used[name] = true
+	}
+	for _, name := range g.tparams {
+		used[name] = true
+	}
+	pnames := make([]string, params.Len())
+	pdecls := make([]string, params.Len())
+	for i := range pnames {
+		name := params.At(i).Name()
+		if name == "" || name == "_" || used[name] {
+			name = fmt.Sprintf("arg%d", i)
+			for j := 2; used[name]; j++ {
+				name = fmt.Sprintf("arg%d_%d", i, j)
+			}
+		}
+		used[name] = true
+		pnames[i] = name
+		pdecls[i] = name + " " + ptypes[i]
+	}
+
+	fmt.Fprintf(w, "func (fake *%s) %s(%s)", fake, m.Name(), strings.Join(pdecls, ", "))
+	switch len(rtypes) {
+	case 0:
+	case 1:
+		fmt.Fprintf(w, " %s", rtypes[0])
+	default:
+		fmt.Fprintf(w, " (%s)", strings.Join(rtypes, ", "))
+	}
+	fmt.Fprintf(w, " {\n")
+
+	args := strings.Join(pnames, ", ")
+	if sig.Variadic() {
+		last := pnames[len(pnames)-1]
+		fmt.Fprintf(w, "callArgs := []interface{}{%s}\n", strings.Join(pnames[:len(pnames)-1], ", "))
+		fmt.Fprintf(w, "for _, arg := range %s {\ncallArgs = append(callArgs, arg)\n}\n", last)
+		args = "callArgs..."
+	}
+	call := fmt.Sprintf("fake.%sStub.Call(%s)", m.Name(), args)
+	if len(rtypes) == 0 {
+		fmt.Fprintf(w, "%s\n}\n", call)
+		return
+	}
+	fmt.Fprintf(w, "ret := %s\n", call)
+	rets := make([]string, len(rtypes))
+	for i, t := range rtypes {
+		rets[i] = fmt.Sprintf("stubzero.Result[%s](ret, %d)", t, i)
+	}
+	fmt.Fprintf(w, "return %s\n}\n", strings.Join(rets, ", "))
+}
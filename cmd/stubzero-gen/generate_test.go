@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		dir   string
+		iface string
+	}{
+		{"store", "Store"},
+		{"logger", "Logger"},
+		{"repo", "Repo"},
+		{"embedded", "WriteFlushCloser"},
+	}
+	for _, c := range cases {
+		t.Run(c.dir, func(t *testing.T) {
+			dir := filepath.Join("testdata", c.dir)
+			got, err := generate(config{Package: "./" + dir, Interface: c.iface})
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join(dir, "fake.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("generated fake does not match %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	got, err := generate(config{
+		Package:       "./testdata/store",
+		Interface:     "Store",
+		Name:          "StoreStub",
+		OutputPackage: "fakes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(got)
+	for _, want := range []string{
+		"package fakes\n",
+		"/cmd/stubzero-gen/testdata/store\"\n",
+		"type StoreStub struct {",
+		"var _ store.Store = (*StoreStub)(nil)",
+		"Get(ctx context.Context, id string) (*store.Item, error)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected generated fake to contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cases := []struct {
+		iface string
+		msg   string
+	}{
+		{"Missing", "no type Missing"},
+		{"Item", "Item is not an interface"},
+	}
+	for _, c := range cases {
+		_, err := generate(config{Package: "./testdata/store", Interface: c.iface})
+		if err == nil || !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("expected error containing %q, got %v", c.msg, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Command stubzero-gen generates a fake for an interface whose methods record
+// their calls on a *stubzero.Stub and return the stub's values.
+//
+// Usage:
+//
+//	stubzero-gen [flags] [package] Interface
+//
+// The package defaults to the current directory, so it can be used with go
+// generate:
+//
+//	//go:generate stubzero-gen -o fake_store_test.go Store
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("stubzero-gen: ")
+
+	out := flag.String("o", "", "write the fake to `file` instead of stdout")
+	pkg := flag.String("pkg", "", "package `name` of the generated file (default the interface's package)")
+	name := flag.String("name", "", "`name` of the fake type (default Fake followed by the interface name)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: stubzero-gen [flags] [package] Interface\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config{Package: ".", Name: *name, OutputPackage: *pkg}
+	switch flag.NArg() {
+	case 1:
+		cfg.Interface = flag.Arg(0)
+	case 2:
+		cfg.Package = flag.Arg(0)
+		cfg.Interface = flag.Arg(1)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
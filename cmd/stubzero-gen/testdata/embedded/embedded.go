@@ -0,0 +1,15 @@
+package embedded
+
+import (
+	"io"
+)
+
+type Flusher interface {
+	Flush() error
+}
+
+type WriteFlushCloser interface {
+	io.WriteCloser
+	Flusher
+	Name() string
+}
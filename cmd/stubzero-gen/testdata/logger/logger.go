@@ -0,0 +1,7 @@
+package logger
+
+type Logger interface {
+	Logf(format string, args ...interface{})
+	Join(sep string, parts ...string) string
+	Fields(callArgs ...int) (n int, err error)
+}
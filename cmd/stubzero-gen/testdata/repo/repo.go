@@ -0,0 +1,8 @@
+package repo
+
+type Repo[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, value V) error
+	All() map[K]V
+	Filter(fn func(V) bool) []V
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+type Item struct {
+	ID      string
+	Updated time.Time
+}
+
+type Store interface {
+	Get(ctx context.Context, id string) (*Item, error)
+	Put(ctx context.Context, item *Item) error
+	Delete(context.Context, string)
+	Count() int
+}
@@ -0,0 +1,11 @@
+// Package stubzero provides helpers for creating manual test stubs and making
+// assertions on how and when they are called, in the style of Sinon.
+//
+// # Contexts
+//
+// The context-aware behaviors, such as BlocksContext, watch the first
+// context.Context among a call's arguments. When it is done before the call
+// would otherwise return, the call returns its configured values with the
+// last one, by convention an error, replaced by the context's error, or the
+// error alone if there are no values.
+package stubzero
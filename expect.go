@@ -0,0 +1,164 @@
+package stubzero
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Expectation declares how many times a stub is expected to be called, with
+// matching args if WithArgs is used. New expectations expect exactly one
+// call.
+type Expectation struct {
+	stub    *Stub
+	args    []interface{}
+	min     int
+	max     int
+	limited bool
+	count   int
+}
+
+// Expect adds an expectation to the stub. Each call counts towards the first
+// expectation, in the order they were added, whose args match and which has
+// not reached its maximum. Calls that no expectation accepts are unexpected
+// and reported by Verify, or immediately after FailOnUnexpected.
+func (s *Stub) Expect() *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &Expectation{stub: s, min: 1, max: 1}
+	s.expected = append(s.expected, e)
+	return e
+}
+
+// FailOnUnexpected reports unexpected calls to t with t.Errorf as they are
+// made rather than only at Verify.
+func (s *Stub) FailOnUnexpected(t testing.TB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failFast = t
+}
+
+// Verify reports unmet expectations and unexpected calls to t and returns
+// whether there were none.
+func (s *Stub) Verify(t testing.TB) bool {
+	t.Helper()
+	s.mu.Lock()
+	var failures []string
+	for _, e := range s.expected {
+		if e.count < e.min {
+			failures = append(failures, fmt.Sprintf(
+				"expected %s to be called %s, but it was called %s",
+				s, e, FormatTimes(e.count),
+			))
+		}
+	}
+	for _, c := range s.unexpected {
+		failures = append(failures, unexpectedCall(c))
+	}
+	s.mu.Unlock()
+	for _, f := range failures {
+		t.Error(f)
+	}
+	return len(failures) == 0
+}
+
+// expect counts c towards the first expectation accepting it and reports
+// whether none did.
+func (s *Stub) expect(c *Call) bool {
+	if len(s.expected) == 0 {
+		return false
+	}
+	for _, e := range s.expected {
+		if c.CalledWith(e.args...) && (e.max < 0 || e.count < e.max) {
+			e.count++
+			return false
+		}
+	}
+	s.unexpected = append(s.unexpected, c)
+	return true
+}
+
+func (e *Expectation) WithArgs(args ...interface{}) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.args = args
+	return e
+}
+
+func (e *Expectation) Times(n int) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	e.min, e.max, e.limited = n, n, true
+	return e
+}
+
+func (e *Expectation) Never() *Expectation {
+	return e.Times(0)
+}
+
+// AtLeast sets the minimum number of calls, with no maximum unless AtMost is
+// also used.
+func (e *Expectation) AtLeast(n int) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	if !e.limited {
+		e.max = -1
+	}
+	e.min, e.limited = n, true
+	return e
+}
+
+// AtMost sets the maximum number of calls, with no minimum unless AtLeast is
+// also used.
+func (e *Expectation) AtMost(n int) *Expectation {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	if !e.limited {
+		e.min = 0
+	}
+	e.max, e.limited = n, true
+	return e
+}
+
+// Returns sets the values returned for calls matching the expectation's args,
+// as Stub.WithArgs(args...).Returns would. Use it after WithArgs.
+func (e *Expectation) Returns(vals ...interface{}) *Expectation {
+	e.stub.mu.Lock()
+	args := e.args
+	e.stub.mu.Unlock()
+	if args == nil {
+		e.stub.Returns(vals...)
+	} else {
+		e.stub.WithArgs(args...).Returns(vals...)
+	}
+	return e
+}
+
+func (e *Expectation) String() string {
+	var n string
+	switch {
+	case e.min == e.max:
+		n = FormatTimes(e.min)
+	case e.max < 0:
+		n = "at least " + FormatTimes(e.min)
+	case e.min == 0:
+		n = "at most " + FormatTimes(e.max)
+	default:
+		n = fmt.Sprintf("%d to %s", e.min, FormatTimes(e.max))
+	}
+	if e.args == nil {
+		return n
+	}
+	return n + " with " + FormatArgs(e.args)
+}
+
+func unexpectedCall(c *Call) string {
+	return "unexpected " + c.String()
+}
+
+// FormatTimes renders a call count as "1 time" or "n times".
+func FormatTimes(n int) string {
+	if n == 1 {
+		return "1 time"
+	}
+	return fmt.Sprintf("%d times", n)
+}
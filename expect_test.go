@@ -0,0 +1,165 @@
+package stubzero
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+func TestStubExpect(t *testing.T) {
+	t.Run("with expectations met", func(t *testing.T) {
+		s := New()
+		s.Expect().WithArgs("a").Times(2)
+		s.Expect().WithArgs(match.Regexp("^b")).AtLeast(1)
+		s.Expect().WithArgs("c").AtMost(1)
+		s.Expect().WithArgs("d").Never()
+		s.Call("a")
+		s.Call("b1")
+		s.Call("a")
+		s.Call("b2")
+		tb := &fakeTB{}
+		if !s.Verify(tb) || tb.failed {
+			t.Errorf("expected expectations to be met, got %v", tb.output)
+		}
+	})
+
+	t.Run("with expectations not met", func(t *testing.T) {
+		s := New()
+		s.Expect().WithArgs("a").Times(2)
+		s.Expect().WithArgs("b").AtLeast(2).AtMost(3)
+		s.Expect()
+		s.Call("a")
+		tb := &fakeTB{}
+		if s.Verify(tb) {
+			t.Error("expected verify to fail")
+		}
+		want := []string{
+			`expected stub to be called 2 times with ("a"), but it was called 1 time`,
+			`expected stub to be called 2 to 3 times with ("b"), but it was called 0 times`,
+			`expected stub to be called 1 time, but it was called 0 times`,
+		}
+		if !reflect.DeepEqual(tb.output, want) {
+			t.Errorf("expected failures %q, got %q", want, tb.output)
+		}
+	})
+
+	t.Run("with unexpected calls", func(t *testing.T) {
+		s := New()
+		s.Expect().WithArgs("a")
+		s.Expect().WithArgs("d").Never()
+		s.Call("a")
+		s.Call("a")
+		s.Call("b", 1)
+		s.Call("d")
+		tb := &fakeTB{}
+		if s.Verify(tb) {
+			t.Error("expected verify to fail")
+		}
+		want := []string{
+			`unexpected stub call 2: ("a")`,
+			`unexpected stub call 3: ("b", 1)`,
+			`unexpected stub call 4: ("d")`,
+		}
+		if !reflect.DeepEqual(tb.output, want) {
+			t.Errorf("expected failures %q, got %q", want, tb.output)
+		}
+	})
+
+	t.Run("with a named stub", func(t *testing.T) {
+		s := NewNamed("repo.Save")
+		s.Expect().WithArgs("a")
+		s.Call("b")
+		tb := &fakeTB{}
+		s.Verify(tb)
+		want := []string{
+			`expected repo.Save to be called 1 time with ("a"), but it was called 0 times`,
+			`unexpected repo.Save call 1: ("b")`,
+		}
+		if !reflect.DeepEqual(tb.output, want) {
+			t.Errorf("expected failures %q, got %q", want, tb.output)
+		}
+	})
+
+	t.Run("with returns", func(t *testing.T) {
+		s := New()
+		s.Expect().WithArgs("a").Returns(1)
+		s.Expect().AtLeast(0).Returns(0)
+		if ret := s.Call("a"); ret[0].(int) != 1 {
+			t.Error("expected to return expectation values for matching args")
+		}
+		if ret := s.Call("b"); ret[0].(int) != 0 {
+			t.Error("expected to return expectation values without args")
+		}
+	})
+
+	t.Run("with fail on unexpected", func(t *testing.T) {
+		s := New()
+		tb := &fakeTB{}
+		s.FailOnUnexpected(tb)
+		s.Expect().WithArgs("a")
+		s.Call("a")
+		if tb.failed {
+			t.Error("expected call to be accepted")
+		}
+		s.Call("b")
+		if !tb.failed || tb.output[0] != `unexpected stub call 2: ("b")` {
+			t.Errorf("expected failure at the unexpected call, got %v", tb.output)
+		}
+	})
+
+	t.Run("with fail on unexpected after the test finished", func(t *testing.T) {
+		s := New()
+		s.FailOnUnexpected(finishedTB{})
+		s.Expect().WithArgs("a")
+		if v := callAndRecover(s, "b"); v == nil {
+			t.Fatal("expected the finished test's Errorf to panic")
+		}
+		done := make(chan int)
+		go func() { done <- s.CallCount() }()
+		select {
+		case n := <-done:
+			if n != 1 || s.InFlight() != 0 || !s.LastCall().Panicked {
+				t.Errorf("expected the call to be recorded as panicking, got %+v", s.LastCall())
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the stub to stay unlocked")
+		}
+	})
+
+	t.Run("with reset", func(t *testing.T) {
+		s := New()
+		s.Expect().Times(1)
+		s.Call()
+		s.Call()
+		s.ResetHistory()
+		s.Call()
+		if tb := (&fakeTB{}); !s.Verify(tb) {
+			t.Errorf("expected reset history to reset counts, got %v", tb.output)
+		}
+		s.Reset()
+		s.Call()
+		if tb := (&fakeTB{}); !s.Verify(tb) {
+			t.Errorf("expected reset to remove expectations, got %v", tb.output)
+		}
+	})
+}
+
+func TestFormatTimes(t *testing.T) {
+	if got := FormatTimes(1); got != "1 time" {
+		t.Errorf("expected 1 time, got %s", got)
+	}
+	if got := FormatTimes(0); got != "0 times" {
+		t.Errorf("expected 0 times, got %s", got)
+	}
+}
+
+// finishedTB panics like a testing.T used after its test has finished.
+type finishedTB struct {
+	testing.TB
+}
+
+func (finishedTB) Errorf(format string, args ...interface{}) {
+	panic("Log in goroutine after test has completed")
+}
@@ -0,0 +1,243 @@
+package stubzero
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+// Mismatch describes an argument of a call that did not match the expected
+// value. Diffs lists each difference found, down to struct fields, map keys
+// and slice indices, as lines such as `arg 0.Tags[1]: expected "a", got "b"`.
+type Mismatch struct {
+	Index      int
+	Expected   interface{}
+	Actual     interface{}
+	Missing    bool
+	Unexpected bool
+	Matcher    string
+	Diffs      []string
+}
+
+// Explanation describes the recorded call closest to matching expected
+// arguments. N is the call's number, counting from 1, and Call is nil if the
+// stub was never called.
+type Explanation struct {
+	Call       *Call
+	N          int
+	Mismatches []Mismatch
+	stub       *Stub
+}
+
+const maxDiffDepth = 10
+
+func (c *Call) ExplainCalledWith(args ...interface{}) []Mismatch {
+	return explainArgs(args, c.Args, false)
+}
+
+func (c *Call) ExplainCalledWithExactly(args ...interface{}) []Mismatch {
+	return explainArgs(args, c.Args, true)
+}
+
+// ExplainCalledWith finds the call with the fewest mismatched arguments, and
+// the earliest of those, to explain why CalledWith returned false.
+func (s *Stub) ExplainCalledWith(args ...interface{}) *Explanation {
+	return s.explain(args, false)
+}
+
+func (s *Stub) ExplainCalledWithExactly(args ...interface{}) *Explanation {
+	return s.explain(args, true)
+}
+
+func (s *Stub) explain(args []interface{}, exact bool) *Explanation {
+	e := &Explanation{stub: s}
+	best := -1
+	for i, c := range s.Calls() {
+		mismatches := explainArgs(args, c.Args, exact)
+		score := 0
+		for _, m := range mismatches {
+			score += 1 + len(m.Diffs)
+		}
+		if best < 0 || score < best {
+			best = score
+			e.Call, e.N, e.Mismatches = c, i+1, mismatches
+		}
+	}
+	return e
+}
+
+func (e *Explanation) Matched() bool {
+	return e.Call != nil && len(e.Mismatches) == 0
+}
+
+func (e *Explanation) String() string {
+	if e.Call == nil {
+		if e.stub == nil {
+			return "stub was not called"
+		}
+		return e.stub.String() + " was not called"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "closest %s", e.Call)
+	for _, m := range e.Mismatches {
+		for _, line := range strings.Split(m.String(), "\n") {
+			fmt.Fprintf(&b, "\n  %s", line)
+		}
+	}
+	return b.String()
+}
+
+func (m Mismatch) String() string {
+	switch {
+	case m.Missing:
+		return fmt.Sprintf("arg %d: expected %s, missing", m.Index, formatArg(m.Expected))
+	case m.Unexpected:
+		return fmt.Sprintf("arg %d: unexpected %#v", m.Index, m.Actual)
+	case m.Matcher != "":
+		return fmt.Sprintf("arg %d: %s rejected %#v", m.Index, m.Matcher, m.Actual)
+	default:
+		return strings.Join(m.Diffs, "\n")
+	}
+}
+
+func explainArgs(expected, actual []interface{}, exact bool) []Mismatch {
+	var mismatches []Mismatch
+	for i, e := range expected {
+		if i >= len(actual) {
+			mismatches = append(mismatches, Mismatch{Index: i, Expected: e, Missing: true})
+			continue
+		}
+		if match.Match(e, actual[i]) {
+			continue
+		}
+		m := Mismatch{Index: i, Expected: e, Actual: actual[i]}
+		if matcher, ok := e.(match.Matcher); ok {
+			m.Matcher = match.Describe(matcher)
+		} else {
+			path := fmt.Sprintf("arg %d", i)
+			m.Diffs = diffValues(path, reflect.ValueOf(e), reflect.ValueOf(actual[i]), 0)
+			if len(m.Diffs) == 0 {
+				m.Diffs = []string{fmt.Sprintf("%s: expected %#v, got %#v", path, e, actual[i])}
+			}
+		}
+		mismatches = append(mismatches, m)
+	}
+	if exact {
+		for i := len(expected); i < len(actual); i++ {
+			mismatches = append(mismatches, Mismatch{Index: i, Actual: actual[i], Unexpected: true})
+		}
+	}
+	return mismatches
+}
+
+func diffValues(path string, e, a reflect.Value, depth int) []string {
+	leaf := func() []string {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, formatValue(e), formatValue(a))}
+	}
+	switch {
+	case !e.IsValid() || !a.IsValid():
+		if e.IsValid() == a.IsValid() {
+			return nil
+		}
+		return leaf()
+	case e.Type() != a.Type():
+		return []string{fmt.Sprintf("%s: expected type %s, got %s", path, e.Type(), a.Type())}
+	case depth >= maxDiffDepth:
+		if valuesEqual(e, a) {
+			return nil
+		}
+		return leaf()
+	}
+
+	var diffs []string
+	switch e.Kind() {
+	case reflect.Struct:
+		for i := 0; i < e.NumField(); i++ {
+			name := path + "." + e.Type().Field(i).Name
+			diffs = append(diffs, diffValues(name, e.Field(i), a.Field(i), depth+1)...)
+		}
+	case reflect.Map:
+		if e.IsNil() != a.IsNil() {
+			return leaf()
+		}
+		keys := e.MapKeys()
+		for _, k := range a.MapKeys() {
+			if !e.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return formatValue(keys[i]) < formatValue(keys[j])
+		})
+		for _, k := range keys {
+			name := fmt.Sprintf("%s[%s]", path, formatValue(k))
+			ev, av := e.MapIndex(k), a.MapIndex(k)
+			switch {
+			case !av.IsValid():
+				diffs = append(diffs, fmt.Sprintf("%s: expected %s, missing", name, formatValue(ev)))
+			case !ev.IsValid():
+				diffs = append(diffs, fmt.Sprintf("%s: unexpected %s", name, formatValue(av)))
+			default:
+				diffs = append(diffs, diffValues(name, ev, av, depth+1)...)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if e.Kind() == reflect.Slice && e.IsNil() != a.IsNil() {
+			return leaf()
+		}
+		if e.Len() != a.Len() {
+			diffs = append(diffs, fmt.Sprintf("%s: expected len %d, got %d", path, e.Len(), a.Len()))
+		}
+		for i := 0; i < e.Len() && i < a.Len(); i++ {
+			name := fmt.Sprintf("%s[%d]", path, i)
+			diffs = append(diffs, diffValues(name, e.Index(i), a.Index(i), depth+1)...)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if e.IsNil() || a.IsNil() {
+			if e.IsNil() == a.IsNil() {
+				return nil
+			}
+			return leaf()
+		}
+		return diffValues(path, e.Elem(), a.Elem(), depth+1)
+	default:
+		if !valuesEqual(e, a) {
+			return leaf()
+		}
+	}
+	return diffs
+}
+
+func valuesEqual(e, a reflect.Value) bool {
+	if e.CanInterface() && a.CanInterface() {
+		return reflect.DeepEqual(e.Interface(), a.Interface())
+	}
+	return formatValue(e) == formatValue(a)
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// FormatArgs renders args as they appear in failure messages, such as
+// `("a", 1, match.Any)`, with %#v for values and descriptions for matchers.
+func FormatArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = formatArg(arg)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func formatArg(arg interface{}) string {
+	if m, ok := arg.(match.Matcher); ok {
+		return match.Describe(m)
+	}
+	return fmt.Sprintf("%#v", arg)
+}
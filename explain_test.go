@@ -0,0 +1,158 @@
+package stubzero
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+type explainUser struct {
+	Name  string
+	Roles []string
+	Meta  map[string]int
+	Boss  *explainUser
+	id    int
+}
+
+func TestCallExplainCalledWith(t *testing.T) {
+	t.Run("with matching args", func(t *testing.T) {
+		c := newCall(1, "a")
+		if m := c.ExplainCalledWith(1); len(m) != 0 {
+			t.Errorf("expected no mismatches, got %v", m)
+		}
+	})
+
+	t.Run("with values", func(t *testing.T) {
+		c := newCall(1, "b")
+		m := c.ExplainCalledWith(1, "a", true)
+		if len(m) != 2 {
+			t.Fatalf("expected 2 mismatches, got %v", m)
+		}
+		if m[0].Index != 1 || m[0].String() != `arg 1: expected "a", got "b"` {
+			t.Errorf("unexpected mismatch %q", m[0])
+		}
+		if !m[1].Missing || m[1].String() != "arg 2: expected true, missing" {
+			t.Errorf("unexpected mismatch %q", m[1])
+		}
+	})
+
+	t.Run("with matchers", func(t *testing.T) {
+		c := newCall("abc", 1)
+		m := c.ExplainCalledWith(match.Regexp("^x"), match.Any)
+		if len(m) != 1 || m[0].Matcher != `match.Regexp("^x")` {
+			t.Fatalf("expected matcher to be named, got %v", m)
+		}
+		if m[0].String() != `arg 0: match.Regexp("^x") rejected "abc"` {
+			t.Errorf("unexpected mismatch %q", m[0])
+		}
+	})
+
+	t.Run("with different types", func(t *testing.T) {
+		m := newCall(int64(1)).ExplainCalledWith(1)
+		if len(m) != 1 || m[0].String() != "arg 0: expected type int, got int64" {
+			t.Errorf("unexpected mismatches %v", m)
+		}
+	})
+
+	t.Run("with nested values", func(t *testing.T) {
+		actual := explainUser{
+			Name:  "ann",
+			Roles: []string{"admin", "dev"},
+			Meta:  map[string]int{"a": 1, "b": 2},
+			Boss:  &explainUser{Name: "bob"},
+			id:    1,
+		}
+		expected := explainUser{
+			Name:  "ann",
+			Roles: []string{"admin", "ops", "qa"},
+			Meta:  map[string]int{"a": 2, "c": 3},
+			Boss:  &explainUser{Name: "bo"},
+			id:    2,
+		}
+		m := newCall(actual).ExplainCalledWith(expected)
+		if len(m) != 1 {
+			t.Fatalf("expected 1 mismatch, got %v", m)
+		}
+		want := []string{
+			`arg 0.Roles: expected len 3, got 2`,
+			`arg 0.Roles[1]: expected "ops", got "dev"`,
+			`arg 0.Meta["a"]: expected 2, got 1`,
+			`arg 0.Meta["b"]: unexpected 2`,
+			`arg 0.Meta["c"]: expected 3, missing`,
+			`arg 0.Boss.Name: expected "bo", got "bob"`,
+			`arg 0.id: expected 2, got 1`,
+		}
+		if !reflect.DeepEqual(m[0].Diffs, want) {
+			t.Errorf("expected diffs:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(m[0].Diffs, "\n"))
+		}
+	})
+
+	t.Run("with nil values", func(t *testing.T) {
+		m := newCall(&explainUser{}, []int(nil)).ExplainCalledWith((*explainUser)(nil), []int{})
+		if len(m) != 2 {
+			t.Fatalf("expected 2 mismatches, got %v", m)
+		}
+		if m[0].String() != "arg 0: expected (*stubzero.explainUser)(nil), got &stubzero.explainUser{Name:\"\", Roles:[]string(nil), Meta:map[string]int(nil), Boss:(*stubzero.explainUser)(nil), id:0}" {
+			t.Errorf("unexpected mismatch %q", m[0])
+		}
+		if m[1].String() != "arg 1: expected []int{}, got []int(nil)" {
+			t.Errorf("unexpected mismatch %q", m[1])
+		}
+	})
+}
+
+func TestCallExplainCalledWithExactly(t *testing.T) {
+	m := newCall(1, 2).ExplainCalledWithExactly(1)
+	if len(m) != 1 || !m[0].Unexpected || m[0].String() != "arg 1: unexpected 2" {
+		t.Errorf("unexpected mismatches %v", m)
+	}
+}
+
+func TestStubExplainCalledWith(t *testing.T) {
+	s := New()
+	e := s.ExplainCalledWith(1)
+	if e.Call != nil || e.Matched() || e.String() != "stub was not called" {
+		t.Error("expected explanation of a stub that was not called")
+	}
+	s.Call(3, "x", false)
+	s.Call(1, "x", true)
+	s.Call(1, "y", true)
+	e = s.ExplainCalledWith(1, "y", false)
+	if e.N != 3 || e.Call != s.NthCall(3) || len(e.Mismatches) != 1 {
+		t.Fatalf("expected closest call to be call 3, got %d", e.N)
+	}
+	want := "closest stub call 3: (1, \"y\", true)\n  arg 2: expected false, got true"
+	if e.String() != want {
+		t.Errorf("expected explanation %q, got %q", want, e.String())
+	}
+	named := NewNamed("repo.Save")
+	if got := named.ExplainCalledWith(1).String(); got != "repo.Save was not called" {
+		t.Errorf("expected explanation to name the stub, got %q", got)
+	}
+	named.Call(2)
+	if got := named.ExplainCalledWith(1).String(); got != "closest repo.Save call 1: (2)\n  arg 0: expected 1, got 2" {
+		t.Errorf("expected explanation to name the stub, got %q", got)
+	}
+	if !s.ExplainCalledWith(1, "x").Matched() {
+		t.Error("expected explanation to match")
+	}
+}
+
+func TestStubExplainCalledWithExactly(t *testing.T) {
+	s := New()
+	s.Call(1, 2, 3)
+	s.Call(1, 3)
+	e := s.ExplainCalledWithExactly(1, 2)
+	if e.N != 1 || len(e.Mismatches) != 1 || !e.Mismatches[0].Unexpected {
+		t.Errorf("expected closest call to be call 1, got %d", e.N)
+	}
+}
+
+func TestFormatArgs(t *testing.T) {
+	got := FormatArgs([]interface{}{"a", 1, nil, match.Any})
+	if want := `("a", 1, <nil>, match.Any)`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
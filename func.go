@@ -0,0 +1,110 @@
+package stubzero
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Func returns a function with the same type as prototype that records its
+// calls on the stub and returns the stub's values converted to the function's
+// result types. Missing values are returned as zero values. Values that can
+// not be assigned to a result type panic with a descriptive error.
+func (s *Stub) Func(prototype interface{}) interface{} {
+	t := reflect.TypeOf(prototype)
+	if t == nil || t.Kind() != reflect.Func {
+		panic(fmt.Errorf("stubzero: Func expects a function prototype, got %T", prototype))
+	}
+	return s.makeFunc(t).Interface()
+}
+
+// Func is like Stub.Func but takes the function type as a type parameter.
+func Func[F any](s *Stub) F {
+	t := reflect.TypeOf((*F)(nil)).Elem()
+	if t.Kind() != reflect.Func {
+		panic(fmt.Errorf("stubzero: Func expects a function type, got %s", t))
+	}
+	return s.makeFunc(t).Interface().(F)
+}
+
+func (s *Stub) makeFunc(t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		return funcResults(t, s.Call(funcArgs(t, in)...))
+	})
+}
+
+func funcArgs(t reflect.Type, in []reflect.Value) []interface{} {
+	args := make([]interface{}, 0, len(in))
+	for i, v := range in {
+		if t.IsVariadic() && i == len(in)-1 {
+			for j := 0; j < v.Len(); j++ {
+				args = append(args, v.Index(j).Interface())
+			}
+			continue
+		}
+		args = append(args, v.Interface())
+	}
+	return args
+}
+
+// Result returns vals[i] converted to T with the same rules Func uses for
+// results, for code that adapts Stub.Call by hand.
+func Result[T any](vals []interface{}, i int) T {
+	var r T
+	rv := reflect.ValueOf(&r).Elem()
+	v, err := resultValue(rv.Type(), vals, i)
+	if err != nil {
+		panic(fmt.Errorf("stubzero: %v", err))
+	}
+	rv.Set(v)
+	return r
+}
+
+func funcResults(t reflect.Type, vals []interface{}) []reflect.Value {
+	if len(vals) > t.NumOut() {
+		panic(fmt.Errorf(
+			"stubzero: %s has %d results, stub returned %d values",
+			t, t.NumOut(), len(vals),
+		))
+	}
+	out := make([]reflect.Value, t.NumOut())
+	for i := range out {
+		v, err := resultValue(t.Out(i), vals, i)
+		if err != nil {
+			panic(fmt.Errorf("stubzero: %v of %s", err, t))
+		}
+		out[i] = v
+	}
+	return out
+}
+
+func resultValue(t reflect.Type, vals []interface{}, i int) (reflect.Value, error) {
+	r := reflect.New(t).Elem()
+	if i >= len(vals) {
+		return r, nil
+	}
+	if vals[i] == nil {
+		if !nillable(t) {
+			return r, fmt.Errorf("cannot use nil as %s in result %d", t, i)
+		}
+		return r, nil
+	}
+	v := reflect.ValueOf(vals[i])
+	if !v.Type().AssignableTo(t) {
+		return r, fmt.Errorf(
+			"cannot use %#v (%s) as %s in result %d",
+			vals[i], v.Type(), t, i,
+		)
+	}
+	r.Set(v)
+	return r, nil
+}
+
+func nillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map,
+		reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
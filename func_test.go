@@ -0,0 +1,124 @@
+package stubzero
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+func recoverError(fn func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = v.(error)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestStubFunc(t *testing.T) {
+	t.Run("with results", func(t *testing.T) {
+		s := New()
+		s.Returns(3, errors.New("fail"))
+		fn := s.Func(func(string, int) (int, error) { return 0, nil }).(func(string, int) (int, error))
+		n, err := fn("a", 1)
+		if n != 3 || err == nil || err.Error() != "fail" {
+			t.Error("expected function to return stub values")
+		}
+		if !s.CalledWithExactly("a", 1) {
+			t.Error("expected function call to be recorded on the stub")
+		}
+	})
+
+	t.Run("with nil and missing results", func(t *testing.T) {
+		s := New()
+		s.Returns(nil)
+		fn := s.Func(func() (*int, error, int) { return nil, nil, 0 }).(func() (*int, error, int))
+		p, err, n := fn()
+		if p != nil || err != nil || n != 0 {
+			t.Error("expected nil and zero values")
+		}
+	})
+
+	t.Run("with variadic args", func(t *testing.T) {
+		s := New()
+		s.WithArgs("f", 1, match.Any).Returns("matched")
+		fn := s.Func(func(string, ...int) string { return "" }).(func(string, ...int) string)
+		if fn("f", 1, 2) != "matched" {
+			t.Error("expected variadic args to be expanded into call args")
+		}
+		if !s.CalledWithExactly("f", 1, 2) {
+			t.Error("expected expanded args to be recorded on the stub")
+		}
+	})
+
+	t.Run("with interface results", func(t *testing.T) {
+		s := New()
+		s.Returns(5)
+		fn := s.Func(func() interface{} { return nil }).(func() interface{})
+		if fn().(int) != 5 {
+			t.Error("expected value to be assigned to interface result")
+		}
+	})
+
+	t.Run("with mismatched results", func(t *testing.T) {
+		cases := []struct {
+			vals []interface{}
+			msg  string
+		}{
+			{[]interface{}{"1"}, `cannot use "1" (string) as int in result 0`},
+			{[]interface{}{nil}, "cannot use nil as int in result 0"},
+			{[]interface{}{1, 2}, "has 1 results, stub returned 2 values"},
+		}
+		for _, c := range cases {
+			s := New()
+			s.Returns(c.vals...)
+			fn := s.Func(func() int { return 0 }).(func() int)
+			err := recoverError(func() { fn() })
+			if err == nil || !strings.Contains(err.Error(), c.msg) {
+				t.Errorf("expected error containing %q, got %v", c.msg, err)
+			}
+		}
+	})
+
+	t.Run("with a non-function prototype", func(t *testing.T) {
+		err := recoverError(func() { New().Func(1) })
+		if err == nil || !strings.Contains(err.Error(), "got int") {
+			t.Errorf("expected a descriptive error, got %v", err)
+		}
+	})
+}
+
+func TestFunc(t *testing.T) {
+	s := New()
+	s.Does(func(args ...interface{}) []interface{} {
+		return []interface{}{len(args[0].([]byte))}
+	})
+	fn := Func[func([]byte) int](s)
+	if fn([]byte("hello")) != 5 {
+		t.Error("expected typed function to return stub values")
+	}
+	err := recoverError(func() { Func[int](s) })
+	if err == nil || !strings.Contains(err.Error(), "got int") {
+		t.Errorf("expected a descriptive error, got %v", err)
+	}
+}
+
+func TestResult(t *testing.T) {
+	vals := []interface{}{1, nil}
+	if Result[int](vals, 0) != 1 {
+		t.Error("expected value to be converted to result type")
+	}
+	if Result[error](vals, 1) != nil {
+		t.Error("expected nil to be converted to nillable result type")
+	}
+	if Result[string](vals, 2) != "" {
+		t.Error("expected zero value for missing result")
+	}
+	err := recoverError(func() { Result[string](vals, 0) })
+	if err == nil || !strings.Contains(err.Error(), "cannot use 1 (int) as string in result 0") {
+		t.Errorf("expected a descriptive error, got %v", err)
+	}
+}
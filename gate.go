@@ -0,0 +1,130 @@
+package stubzero
+
+import (
+	"context"
+)
+
+// Gate holds calls to a stub until the test releases them. Calls are
+// recorded as soon as they enter, so WaitForCalls can tell when they are
+// blocked.
+type Gate struct {
+	stub    *Stub
+	permits int
+	open    bool
+	ctx     bool
+	release chan struct{}
+}
+
+// Blocks makes every following call wait at a new gate before producing its
+// result. Calling it again, or Reset, releases the previous gate.
+func (s *Stub) Blocks() *Gate {
+	return s.block(false)
+}
+
+// BlocksContext is like Blocks, but a call whose arguments include a
+// context.Context also stops waiting when that context is done, returning the
+// context's error as described in the package documentation.
+func (s *Stub) BlocksContext() *Gate {
+	return s.block(true)
+}
+
+func (s *Stub) block(ctx bool) *Gate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gate != nil {
+		s.gate.openLocked()
+	}
+	s.gate = &Gate{stub: s, ctx: ctx, release: make(chan struct{})}
+	return s.gate
+}
+
+// Release lets n blocked or future calls through.
+func (g *Gate) Release(n int) {
+	g.stub.mu.Lock()
+	defer g.stub.mu.Unlock()
+	g.permits += n
+	g.wakeLocked()
+}
+
+// ReleaseAll lets every blocked and future call through.
+func (g *Gate) ReleaseAll() {
+	g.stub.mu.Lock()
+	defer g.stub.mu.Unlock()
+	g.openLocked()
+}
+
+func (g *Gate) openLocked() {
+	g.open = true
+	g.wakeLocked()
+}
+
+func (g *Gate) wakeLocked() {
+	close(g.release)
+	g.release = make(chan struct{})
+}
+
+// wait blocks until the gate lets the call through, returning the error of
+// the call's context if it is done first.
+func (g *Gate) wait(args []interface{}) error {
+	var done <-chan struct{}
+	ctx := contextArg(args)
+	if g.ctx && ctx != nil {
+		done = ctx.Done()
+	}
+	for {
+		g.stub.mu.Lock()
+		if g.open {
+			g.stub.mu.Unlock()
+			return nil
+		}
+		if g.permits > 0 {
+			g.permits--
+			g.stub.mu.Unlock()
+			return nil
+		}
+		release := g.release
+		g.stub.mu.Unlock()
+		select {
+		case <-release:
+		case <-done:
+			return ctx.Err()
+		}
+	}
+}
+
+// InFlight returns the number of calls that have entered the stub but not
+// yet returned or panicked.
+func (s *Stub) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight
+}
+
+// MaxInFlight returns the largest number of calls that were in flight at
+// once since the stub was created or its history last reset.
+func (s *Stub) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+func contextArg(args []interface{}) context.Context {
+	for _, arg := range args {
+		if ctx, ok := arg.(context.Context); ok {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// withErr builds the result of a call cut short by its context, as described
+// in the package documentation.
+func withErr(vals []interface{}, err error) []interface{} {
+	if len(vals) == 0 {
+		return []interface{}{err}
+	}
+	out := make([]interface{}, len(vals))
+	copy(out, vals)
+	out[len(out)-1] = err
+	return out
+}
@@ -0,0 +1,149 @@
+package stubzero
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func waitCalls(t *testing.T, s *Stub, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.WaitForCalls(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStubBlocks(t *testing.T) {
+	s := New()
+	s.Returns(1)
+	g := s.Blocks()
+	results := make(chan []interface{}, 3)
+	for i := 0; i < 3; i++ {
+		go func() { results <- s.Call() }()
+	}
+	waitCalls(t, s, 3)
+	if n := s.InFlight(); n != 3 {
+		t.Errorf("expected 3 calls in flight, got %d", n)
+	}
+	select {
+	case <-results:
+		t.Fatal("expected calls to block until released")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	g.Release(2)
+	for i := 0; i < 2; i++ {
+		if vals := <-results; len(vals) != 1 || vals[0] != 1 {
+			t.Errorf("expected released call to return [1], got %v", vals)
+		}
+	}
+	select {
+	case <-results:
+		t.Fatal("expected third call to stay blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if n := s.InFlight(); n != 1 {
+		t.Errorf("expected 1 call in flight, got %d", n)
+	}
+
+	g.ReleaseAll()
+	<-results
+	if vals := s.Call(); len(vals) != 1 {
+		t.Errorf("expected calls after ReleaseAll to pass, got %v", vals)
+	}
+	if n := s.InFlight(); n != 0 {
+		t.Errorf("expected no calls in flight, got %d", n)
+	}
+	if n := s.MaxInFlight(); n != 3 {
+		t.Errorf("expected at most 3 calls in flight, got %d", n)
+	}
+}
+
+func TestGateReleaseBeforeCall(t *testing.T) {
+	s := New()
+	s.Blocks().Release(1)
+	s.Call()
+	done := make(chan struct{})
+	go func() {
+		s.Call()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected second call to block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Reset()
+	<-done
+}
+
+func TestStubBlocksContext(t *testing.T) {
+	s := New()
+	s.Returns("value", nil)
+	g := s.BlocksContext()
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan []interface{})
+	go func() { results <- s.Call(ctx, "key") }()
+	waitCalls(t, s, 1)
+	cancel()
+	vals := <-results
+	if len(vals) != 2 || vals[0] != "value" || vals[1] != context.Canceled {
+		t.Errorf("expected [value, context.Canceled], got %v", vals)
+	}
+
+	s.Returns()
+	go func() { results <- s.Call(ctx) }()
+	if vals := <-results; len(vals) != 1 || vals[0] != context.Canceled {
+		t.Errorf("expected [context.Canceled], got %v", vals)
+	}
+
+	go func() { results <- s.Call(context.Background()) }()
+	waitCalls(t, s, 3)
+	g.Release(1)
+	if vals := <-results; len(vals) != 0 {
+		t.Errorf("expected released call to return no values, got %v", vals)
+	}
+
+	t.Run("Blocks ignores context", func(t *testing.T) {
+		s := New()
+		g := s.Blocks()
+		done := make(chan struct{})
+		go func() {
+			s.Call(ctx)
+			close(done)
+		}()
+		select {
+		case <-done:
+			t.Fatal("expected call to block despite cancelled context")
+		case <-time.After(10 * time.Millisecond):
+		}
+		g.ReleaseAll()
+		<-done
+	})
+}
+
+func TestStubMaxInFlight(t *testing.T) {
+	s := New()
+	g := s.Blocks()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Call()
+		}()
+	}
+	waitCalls(t, s, 20)
+	g.ReleaseAll()
+	wg.Wait()
+	if n := s.MaxInFlight(); n != 20 {
+		t.Errorf("expected at most 20 calls in flight, got %d", n)
+	}
+	s.ResetHistory()
+	if n := s.MaxInFlight(); n != 0 {
+		t.Errorf("expected ResetHistory to reset max in flight, got %d", n)
+	}
+}
@@ -0,0 +1,97 @@
+package stubzero
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Distribution returns the latency to add to each call.
+type Distribution func() time.Duration
+
+func Fixed(d time.Duration) Distribution {
+	return func() time.Duration {
+		return d
+	}
+}
+
+// Uniform returns latencies spread evenly over [min, max).
+func Uniform(min, max time.Duration) Distribution {
+	return uniform(min, max, rand.Int63n)
+}
+
+// UniformSeed is like Uniform, but draws from a source seeded with seed so
+// that the same seed gives the same latencies.
+func UniformSeed(min, max time.Duration, seed int64) Distribution {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return uniform(min, max, func(n int64) int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.Int63n(n)
+	})
+}
+
+func uniform(min, max time.Duration, int63n func(int64) int64) Distribution {
+	if max <= min {
+		return Fixed(min)
+	}
+	return func() time.Duration {
+		return min + time.Duration(int63n(int64(max-min)))
+	}
+}
+
+// Latency holds every following call for a duration drawn from dist before
+// it returns, measured by the stub's clock and added to any ReturnsAfter
+// delay. A nil dist removes the latency.
+func (s *Stub) Latency(dist Distribution) {
+	s.latency(dist, false)
+}
+
+// LatencyContext is like Latency, but a call returns early with the context's
+// error if its context is done first.
+func (s *Stub) LatencyContext(dist Distribution) {
+	s.latency(dist, true)
+}
+
+func (s *Stub) latency(dist Distribution, ctx bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dist = dist
+	s.distCtx = ctx
+}
+
+// hold returns how long to hold a call answered by r. The stub's latency is
+// only drawn by sleep, as dist may use the stub and must run without its lock.
+func (s *Stub) hold(r *response) delay {
+	after := r.after
+	if s.dist != nil {
+		after.dist = s.dist
+		after.ctx = after.ctx || s.distCtx
+	}
+	return after
+}
+
+// sleep holds a call for after, returning the error of the call's context if
+// it is done first and after allows it.
+func (s *Stub) sleep(after delay, args []interface{}) error {
+	if after.dist != nil {
+		after.d += after.dist()
+	}
+	if after.d <= 0 {
+		return nil
+	}
+	var done <-chan struct{}
+	ctx := contextArg(args)
+	if after.ctx && ctx != nil {
+		done = ctx.Done()
+	}
+	t := s.clock.NewTimer(after.d)
+	defer t.Stop()
+	select {
+	case <-t.C():
+		return nil
+	case <-done:
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package stubzero
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/clock"
+)
+
+func TestFixed(t *testing.T) {
+	if d := Fixed(time.Second)(); d != time.Second {
+		t.Errorf("expected 1s, got %s", d)
+	}
+}
+
+func TestUniform(t *testing.T) {
+	dist := Uniform(time.Second, 2*time.Second)
+	for i := 0; i < 100; i++ {
+		if d := dist(); d < time.Second || d >= 2*time.Second {
+			t.Fatalf("expected latency in [1s, 2s), got %s", d)
+		}
+	}
+	if d := Uniform(time.Second, time.Second)(); d != time.Second {
+		t.Errorf("expected an empty range to give its minimum, got %s", d)
+	}
+}
+
+func TestUniformSeed(t *testing.T) {
+	a, b := UniformSeed(0, time.Hour, 42), UniformSeed(0, time.Hour, 42)
+	for i := 0; i < 10; i++ {
+		if da, db := a(), b(); da != db {
+			t.Fatalf("expected the same seed to give the same latencies, got %s and %s", da, db)
+		}
+	}
+}
+
+func TestStubLatency(t *testing.T) {
+	fake := clock.NewFake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := New(WithClock(fake))
+	s.Latency(Fixed(time.Second))
+	s.ReturnsAfter(time.Second, 1)
+	results := make(chan []interface{})
+	go func() { results <- s.Call() }()
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	select {
+	case <-results:
+		t.Fatal("expected latency to add to the ReturnsAfter delay")
+	default:
+	}
+	fake.Advance(time.Second)
+	if vals := <-results; len(vals) != 1 || vals[0] != 1 {
+		t.Errorf("expected [1], got %v", vals)
+	}
+	if d := s.LastCall().Duration; d != 2*time.Second {
+		t.Errorf("expected call held for 2s, got %s", d)
+	}
+
+	s.Latency(nil)
+	s.Returns(1)
+	if vals := s.Call(); len(vals) != 1 {
+		t.Errorf("expected [1], got %v", vals)
+	}
+
+	t.Run("ignores context", func(t *testing.T) {
+		s := New(WithClock(fake))
+		s.Latency(Fixed(time.Second))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		go func() { results <- s.Call(ctx) }()
+		fake.BlockUntil(1)
+		fake.Advance(time.Second)
+		if vals := <-results; len(vals) != 0 {
+			t.Errorf("expected no values, got %v", vals)
+		}
+	})
+
+	t.Run("with a distribution using the stub", func(t *testing.T) {
+		s := New(WithClock(fake))
+		var counts []int
+		s.Latency(func() time.Duration {
+			counts = append(counts, s.CallCount())
+			return 0
+		})
+		s.Call()
+		s.Call()
+		if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+			t.Errorf("expected latency drawn after each call is recorded, got %v", counts)
+		}
+	})
+}
+
+func TestStubLatencyContext(t *testing.T) {
+	fake := clock.NewFake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := New(WithClock(fake))
+	s.LatencyContext(UniformSeed(time.Second, time.Minute, 1))
+	s.Returns(1, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	vals := s.Call(ctx)
+	if len(vals) != 2 || vals[0] != 1 || vals[1] != context.DeadlineExceeded {
+		t.Errorf("expected [1, context.DeadlineExceeded], got %v", vals)
+	}
+	s.Reset()
+	if vals := s.Call(ctx); len(vals) != 0 {
+		t.Errorf("expected Reset to remove the latency, got %v", vals)
+	}
+}
@@ -1,29 +1,37 @@
 package match
 
 func And(matchers ...Matcher) Matcher {
-	return func(v interface{}) bool {
+	return describe(func() string { return describef("And", matcherArgs(matchers)...) }, func(v interface{}) bool {
 		for _, matcher := range matchers {
 			if !matcher(v) {
 				return false
 			}
 		}
 		return true
-	}
+	})
 }
 
 func Or(matchers ...Matcher) Matcher {
-	return func(v interface{}) bool {
+	return describe(func() string { return describef("Or", matcherArgs(matchers)...) }, func(v interface{}) bool {
 		for _, matcher := range matchers {
 			if matcher(v) {
 				return true
 			}
 		}
 		return false
-	}
+	})
 }
 
 func Xor(m1, m2 Matcher) Matcher {
-	return func(v interface{}) bool {
+	return describe(func() string { return describef("Xor", m1, m2) }, func(v interface{}) bool {
 		return m1(v) != m2(v)
+	})
+}
+
+func matcherArgs(matchers []Matcher) []interface{} {
+	args := make([]interface{}, len(matchers))
+	for i, m := range matchers {
+		args[i] = m
 	}
+	return args
 }
@@ -0,0 +1,79 @@
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// probe is passed to described matchers to ask for their description.
+type probe struct {
+	desc string
+}
+
+// describedPC is the code shared by every matcher made by describe, which
+// lets Describe probe only those.
+var describedPC = reflect.ValueOf(describe(nil, nil)).Pointer()
+
+// describe wraps fn in a matcher described by desc. desc is only called when
+// the matcher is described, so that matchers that never are don't pay for
+// formatting their operands. describe must not be inlined, as inlining would
+// give each caller its own copy of the closure's code.
+//
+//go:noinline
+func describe(desc func() string, fn func(interface{}) bool) Matcher {
+	return func(v interface{}) bool {
+		if p, ok := v.(*probe); ok {
+			p.desc = desc()
+			return false
+		}
+		return fn(v)
+	}
+}
+
+// Named returns a matcher that uses fn and is described as name in failure
+// messages.
+func Named(name string, fn func(interface{}) bool) Matcher {
+	return describe(func() string { return name }, fn)
+}
+
+// Describe returns a readable description of m, such as
+// `match.Key("id", match.Any)`. Matchers made with Custom are described by the
+// name of their function, or as match.Custom if it is anonymous.
+func Describe(m Matcher) string {
+	if m == nil {
+		return "<nil>"
+	}
+	pc := reflect.ValueOf(m).Pointer()
+	if pc == describedPC {
+		p := &probe{}
+		m(p)
+		return p.desc
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "match.Custom"
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	if strings.Contains(name, ".func") {
+		return "match.Custom"
+	}
+	return name
+}
+
+func describef(name string, args ...interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = format(arg)
+	}
+	return "match." + name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+func format(v interface{}) string {
+	if m, ok := v.(Matcher); ok {
+		return Describe(m)
+	}
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,76 @@
+package match
+
+import (
+	"regexp"
+	"testing"
+)
+
+func isOne(v interface{}) bool {
+	return v == 1
+}
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		m    Matcher
+		want string
+	}{
+		{Any, "match.Any"},
+		{Regexp("^a"), `match.Regexp("^a")`},
+		{Regexp(regexp.MustCompile(`\d+`)), `match.Regexp("\\d+")`},
+		{Key("id", 7), `match.Key("id", 7)`},
+		{Contains(Key("id", Any)), `match.Contains(match.Key("id", match.Any))`},
+		{Field("Name", "a"), `match.Field("Name", "a")`},
+		{And(Any, Regexp("a")), `match.And(match.Any, match.Regexp("a"))`},
+		{Or(), "match.Or()"},
+		{Xor(Any, Custom(isOne)), "match.Xor(match.Any, match.isOne)"},
+		{Custom(func(v interface{}) bool { return true }), "match.Custom"},
+		{Named("isOne", isOne), "isOne"},
+	}
+	for _, c := range cases {
+		if got := Describe(c.m); got != c.want {
+			t.Errorf("expected %s, got %s", c.want, got)
+		}
+	}
+}
+
+func TestDescribeDoesNotCallCustomMatchers(t *testing.T) {
+	called := false
+	Describe(Custom(func(v interface{}) bool {
+		called = true
+		return false
+	}))
+	if called {
+		t.Error("expected Describe to not call a custom matcher")
+	}
+}
+
+func TestNamed(t *testing.T) {
+	m := Named("isOne", isOne)
+	if !m(1) || m(2) {
+		t.Error("expected named matcher to use its function")
+	}
+}
+
+type formatCounter struct {
+	n *int
+}
+
+func (f formatCounter) GoString() string {
+	*f.n++
+	return "formatCounter"
+}
+
+func TestDescribeIsLazy(t *testing.T) {
+	n := 0
+	v := formatCounter{&n}
+	m := And(Key("id", v), Contains(v), Field("F", v), Or(Xor(Key("k", v), Any)))
+	m(map[string]int{})
+	if n != 0 {
+		t.Fatalf("expected operands to be formatted only when described, got %d", n)
+	}
+	want := `match.And(match.Key("id", formatCounter), match.Contains(formatCounter), ` +
+		`match.Field("F", formatCounter), match.Or(match.Xor(match.Key("k", formatCounter), match.Any)))`
+	if got := Describe(m); got != want || n != 4 {
+		t.Errorf("expected %s with 4 operands formatted, got %s with %d", want, got, n)
+	}
+}
@@ -7,9 +7,9 @@ import (
 
 type Matcher func(interface{}) bool
 
-var Any Matcher = func(_ interface{}) bool {
+var Any Matcher = describe(func() string { return "match.Any" }, func(_ interface{}) bool {
 	return true
-}
+})
 
 func Match(v1, v2 interface{}) bool {
 	if reflect.TypeOf(v1) == reflect.TypeOf(Any) {
@@ -24,7 +24,7 @@ func Regexp(exp interface{}) Matcher {
 	if !ok {
 		re = regexp.MustCompile(exp.(string))
 	}
-	return func(val interface{}) bool {
+	return describe(func() string { return describef("Regexp", re.String()) }, func(val interface{}) bool {
 		switch val := val.(type) {
 		case []byte:
 			return re.Match(val)
@@ -33,11 +33,11 @@ func Regexp(exp interface{}) Matcher {
 		default:
 			return false
 		}
-	}
+	})
 }
 
 func Key(k, v interface{}) Matcher {
-	return func(m interface{}) bool {
+	return describe(func() string { return describef("Key", k, v) }, func(m interface{}) bool {
 		t := reflect.TypeOf(m)
 		if t.Kind() != reflect.Map {
 			return false
@@ -48,11 +48,11 @@ func Key(k, v interface{}) Matcher {
 			return false
 		}
 		return Match(v, mkv.Interface())
-	}
+	})
 }
 
 func Contains(v interface{}) Matcher {
-	return func(s interface{}) bool {
+	return describe(func() string { return describef("Contains", v) }, func(s interface{}) bool {
 		if reflect.TypeOf(s).Kind() != reflect.Slice {
 			return false
 		}
@@ -63,11 +63,11 @@ func Contains(v interface{}) Matcher {
 			}
 		}
 		return false
-	}
+	})
 }
 
 func Field(n string, v interface{}) Matcher {
-	return func(s interface{}) bool {
+	return describe(func() string { return describef("Field", n, v) }, func(s interface{}) bool {
 		t := reflect.TypeOf(s)
 		if t.Kind() != reflect.Struct {
 			return false
@@ -78,7 +78,7 @@ func Field(n string, v interface{}) Matcher {
 			return false
 		}
 		return Match(v, sv.FieldByName(n).Interface())
-	}
+	})
 }
 
 func Custom(m func(interface{}) bool) Matcher {
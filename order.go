@@ -0,0 +1,179 @@
+package stubzero
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OrderStep is one expected call in an InOrder or InStrictOrder sequence.
+type OrderStep struct {
+	stub *Stub
+	args []interface{}
+	min  int
+	many bool
+}
+
+// OrderError reports the first step of a sequence that was not satisfied.
+// Step counts from 1. Call is the call that broke the order: an unexpected
+// call in strict order, or the step's own call if it came too early. It is nil
+// if the step's call was never made.
+type OrderError struct {
+	Step int
+	Call *Call
+	want string
+	got  string
+	// before is the step, counting from 1, that Call was made before.
+	before int
+}
+
+// Called returns a step expecting one call to s whose leading arguments match
+// args, compared with match.Match.
+func Called(s *Stub, args ...interface{}) *OrderStep {
+	return &OrderStep{stub: s, args: args, min: 1}
+}
+
+// AnyTimes makes the step match zero or more consecutive calls. Like
+// AtLeastOnce, it stops repeating as soon as a call matches the next step,
+// once its minimum is met.
+func (st *OrderStep) AnyTimes() *OrderStep {
+	st.min = 0
+	st.many = true
+	return st
+}
+
+// AtLeastOnce makes the step match one or more consecutive calls.
+func (st *OrderStep) AtLeastOnce() *OrderStep {
+	st.min = 1
+	st.many = true
+	return st
+}
+
+func (st *OrderStep) matches(s *Stub, c *Call) bool {
+	return st.stub == s && c.CalledWith(st.args...)
+}
+
+// InOrder checks that the steps' calls were made in order, ignoring any other
+// calls made in between.
+func InOrder(steps ...*OrderStep) error {
+	return verifyOrder(steps, false)
+}
+
+// InStrictOrder checks that the steps' calls were made in order and that no
+// other calls were made on the steps' stubs in between. Other calls on those
+// stubs made before the first step's call fail it too, while calls made after
+// the last step's are ignored.
+func InStrictOrder(steps ...*OrderStep) error {
+	return verifyOrder(steps, true)
+}
+
+type stubCall struct {
+	stub *Stub
+	call *Call
+}
+
+func verifyOrder(steps []*OrderStep, strict bool) error {
+	var calls []stubCall
+	ids := make(map[*Stub]int)
+	for _, st := range steps {
+		if _, ok := ids[st.stub]; ok {
+			continue
+		}
+		ids[st.stub] = len(ids) + 1
+		for _, c := range st.stub.Calls() {
+			calls = append(calls, stubCall{st.stub, c})
+		}
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].call.CalledBefore(calls[j].call)
+	})
+
+	label := func(s *Stub) string {
+		if s.name != "" {
+			return s.name
+		}
+		return fmt.Sprintf("stub #%d", ids[s])
+	}
+	fail := func(i int, sc *stubCall) error {
+		st := steps[i]
+		err := &OrderError{
+			Step: i + 1,
+			want: fmt.Sprintf("%s called with %s", label(st.stub), FormatArgs(st.args)),
+		}
+		if sc != nil {
+			err.Call = sc.call
+			err.got = fmt.Sprintf("%s called with %s", label(sc.stub), FormatArgs(sc.call.Args))
+		}
+		return err
+	}
+
+	// first holds the first call matched by each step, to find the step an
+	// early call came before.
+	first := make([]*Call, len(steps))
+	early := func(i int) error {
+		for _, sc := range calls {
+			if !steps[i].matches(sc.stub, sc.call) {
+				continue
+			}
+			for j := 0; j < i; j++ {
+				if first[j] != nil && sc.call.CalledBefore(first[j]) {
+					err := fail(i, nil).(*OrderError)
+					err.Call, err.before = sc.call, j+1
+					return err
+				}
+			}
+		}
+		return fail(i, nil)
+	}
+
+	i, count := 0, 0
+	for _, sc := range calls {
+		for i < len(steps) {
+			st := steps[i]
+			next := i+1 < len(steps) && steps[i+1].matches(sc.stub, sc.call)
+			if st.many && count >= st.min && next {
+				i, count = i+1, 0
+				continue
+			}
+			if st.matches(sc.stub, sc.call) {
+				if count == 0 {
+					first[i] = sc.call
+				}
+				count++
+				if !st.many {
+					i, count = i+1, 0
+				}
+				break
+			}
+			if count >= st.min {
+				i, count = i+1, 0
+				continue
+			}
+			if strict {
+				return fail(i, &sc)
+			}
+			break
+		}
+		if i == len(steps) {
+			break
+		}
+	}
+	for ; i < len(steps); i, count = i+1, 0 {
+		if count < steps[i].min {
+			return early(i)
+		}
+	}
+	return nil
+}
+
+func (e *OrderError) Error() string {
+	if e.Call == nil {
+		return fmt.Sprintf("stubzero: step %d expected %s, but it was not called", e.Step, e.want)
+	}
+	if e.before > 0 {
+		return fmt.Sprintf(
+			"stubzero: step %d expected %s, but it was called before step %d (seq %d)",
+			e.Step, e.want, e.before, e.Call.Seq,
+		)
+	}
+	return fmt.Sprintf("stubzero: step %d expected %s, got %s", e.Step, e.want, e.got)
+}
@@ -0,0 +1,163 @@
+package stubzero
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+func TestInOrder(t *testing.T) {
+	t.Run("with calls in order", func(t *testing.T) {
+		open, write, closeStub := New(), New(), New()
+		open.Call("file")
+		write.Call("a")
+		write.Call("b")
+		closeStub.Call()
+		err := InOrder(
+			Called(open, "file"),
+			Called(write, match.Any).AtLeastOnce(),
+			Called(closeStub),
+		)
+		if err != nil {
+			t.Errorf("expected calls to be in order, got %v", err)
+		}
+	})
+
+	t.Run("with interleaved calls", func(t *testing.T) {
+		open, write, closeStub := New(), New(), New()
+		write.Call("early")
+		open.Call("file")
+		write.Call("a")
+		open.Call("other")
+		closeStub.Call()
+		if err := InOrder(Called(open, "file"), Called(closeStub)); err != nil {
+			t.Errorf("expected interleaved calls to be ignored, got %v", err)
+		}
+	})
+
+	t.Run("with optional steps", func(t *testing.T) {
+		open, write, closeStub := New(), New(), New()
+		open.Call()
+		closeStub.Call()
+		err := InOrder(Called(open), Called(write).AnyTimes(), Called(closeStub))
+		if err != nil {
+			t.Errorf("expected optional step to be skipped, got %v", err)
+		}
+	})
+
+	t.Run("with calls out of order", func(t *testing.T) {
+		open, closeStub := New(), New()
+		closeStub.Call()
+		open.Call("file")
+		err := InOrder(Called(open, "file"), Called(closeStub))
+		oerr, ok := err.(*OrderError)
+		if !ok || oerr.Step != 2 || oerr.Call != closeStub.FirstCall() {
+			t.Fatalf("expected step 2 to fail on the early close, got %v", err)
+		}
+		want := fmt.Sprintf(
+			"stubzero: step 2 expected stub #2 called with (), but it was called before step 1 (seq %d)",
+			closeStub.FirstCall().Seq,
+		)
+		if err.Error() != want {
+			t.Errorf("expected error %q, got %q", want, err.Error())
+		}
+	})
+
+	t.Run("with a step never called", func(t *testing.T) {
+		open, closeStub := New(), New()
+		open.Call("file")
+		err := InOrder(Called(open, "file"), Called(closeStub))
+		want := "stubzero: step 2 expected stub #2 called with (), but it was not called"
+		if oerr, ok := err.(*OrderError); !ok || oerr.Call != nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("with repeated steps followed by the same stub", func(t *testing.T) {
+		write := New()
+		write.Call(2)
+		if err := InOrder(Called(write).AnyTimes(), Called(write, 2)); err != nil {
+			t.Errorf("expected AnyTimes to stop at the next step, got %v", err)
+		}
+		write.Call(1)
+		write.Call(2)
+		write.Call(1)
+		err := InOrder(Called(write).AtLeastOnce(), Called(write, 2), Called(write, 1))
+		if err != nil {
+			t.Errorf("expected AtLeastOnce to stop at the next step, got %v", err)
+		}
+		err = InStrictOrder(Called(write).AnyTimes(), Called(write, 2), Called(write, 2))
+		if err == nil {
+			t.Error("expected repeated step to stop at the first call matching the next step")
+		}
+	})
+
+	t.Run("with args not matching", func(t *testing.T) {
+		open := New()
+		open.Call("other")
+		err := InOrder(Called(open, "file"))
+		if oerr, ok := err.(*OrderError); !ok || oerr.Step != 1 {
+			t.Errorf("expected step 1 to fail, got %v", err)
+		}
+	})
+}
+
+func TestInStrictOrder(t *testing.T) {
+	t.Run("with calls in order", func(t *testing.T) {
+		open, write, closeStub, other := New(), New(), New(), New()
+		open.Call("file")
+		other.Call()
+		write.Call("a")
+		write.Call("b")
+		closeStub.Call()
+		err := InStrictOrder(
+			Called(open, "file"),
+			Called(write).AnyTimes(),
+			Called(closeStub),
+		)
+		if err != nil {
+			t.Errorf("expected calls to be in order, got %v", err)
+		}
+	})
+
+	t.Run("with interleaved calls", func(t *testing.T) {
+		open, closeStub := New(), New()
+		open.Call("file")
+		open.Call("again")
+		closeStub.Call()
+		open.Call("after")
+		err := InStrictOrder(Called(open, "file"), Called(closeStub))
+		oerr, ok := err.(*OrderError)
+		if !ok || oerr.Step != 2 || oerr.Call != open.NthCall(2) {
+			t.Fatalf("expected step 2 to fail on the second open, got %v", err)
+		}
+		err = InStrictOrder(Called(open), Called(open), Called(closeStub))
+		if err != nil {
+			t.Errorf("expected calls after the last step to be ignored, got %v", err)
+		}
+	})
+
+	t.Run("with unexpected calls on the same stub", func(t *testing.T) {
+		write := New()
+		write.Call("a")
+		write.Call("x")
+		write.Call("b")
+		err := InStrictOrder(Called(write, "a"), Called(write, "b"))
+		want := `stubzero: step 2 expected stub #1 called with ("b"), got stub #1 called with ("x")`
+		if err == nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("with named stubs and matchers", func(t *testing.T) {
+		open, write := NewNamed("file.Open"), NewNamed("file.Write")
+		write.Call("x")
+		open.Call("a.txt")
+		err := InStrictOrder(Called(open, match.Regexp(`\.txt$`)), Called(write, match.Any))
+		want := `stubzero: step 1 expected file.Open called with (match.Regexp("\\.txt$")), got file.Write called with ("x")`
+		if err == nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package stubzero
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var replacements = struct {
+	sync.Mutex
+	owners map[uintptr][]testing.TB
+}{owners: make(map[uintptr][]testing.TB)}
+
+// Replace sets the function variable target points to to replacement, such as
+// a function returned by Func, and restores it when t finishes. It fails t if
+// target is not a pointer to a function variable, if replacement has a
+// different type, or if the variable is already replaced by a test that is not
+// t or one of its parents, such as a parallel test.
+func Replace(t testing.TB, target, replacement interface{}) {
+	t.Helper()
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() || tv.Elem().Kind() != reflect.Func {
+		t.Fatalf("stubzero: Replace target must be a non-nil pointer to a function variable, got %T", target)
+	}
+	key := tv.Pointer()
+	if owner := claim(key, t); owner != nil {
+		t.Fatalf("stubzero: %s variable is already replaced by %s", tv.Elem().Type(), owner.Name())
+	}
+	restore, err := replace(target, replacement)
+	if err != nil {
+		release(key, t)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		restore()
+		release(key, t)
+	})
+}
+
+func claim(key uintptr, t testing.TB) testing.TB {
+	replacements.Lock()
+	defer replacements.Unlock()
+	owners := replacements.owners[key]
+	if len(owners) > 0 {
+		owner := owners[len(owners)-1]
+		if owner != t && !strings.HasPrefix(t.Name(), owner.Name()+"/") {
+			return owner
+		}
+	}
+	replacements.owners[key] = append(owners, t)
+	return nil
+}
+
+func release(key uintptr, t testing.TB) {
+	replacements.Lock()
+	defer replacements.Unlock()
+	owners := replacements.owners[key]
+	for i := len(owners) - 1; i >= 0; i-- {
+		if owners[i] == t {
+			owners = append(owners[:i], owners[i+1:]...)
+			break
+		}
+	}
+	if len(owners) == 0 {
+		delete(replacements.owners, key)
+		return
+	}
+	replacements.owners[key] = owners
+}
@@ -0,0 +1,151 @@
+package stubzero
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+var replaceNow = time.Now
+
+type fakeTB struct {
+	testing.TB
+	name     string
+	failed   bool
+	output   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Name() string {
+	return tb.name
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanups = append(tb.cleanups, fn)
+}
+
+func (tb *fakeTB) Error(args ...interface{}) {
+	tb.failed = true
+	tb.output = append(tb.output, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.Error(fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Fatal(args ...interface{}) {
+	tb.Error(args...)
+	runtime.Goexit()
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.Fatal(fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) run(fn func(tb testing.TB)) *fakeTB {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+	return tb
+}
+
+func (tb *fakeTB) finish() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+	tb.cleanups = nil
+}
+
+func TestReplace(t *testing.T) {
+	t.Run("with a stub function", func(t *testing.T) {
+		fixed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := New()
+		s.Returns(fixed)
+		tb := &fakeTB{name: "TestA"}
+		tb.run(func(tb testing.TB) {
+			Replace(tb, &replaceNow, Func[func() time.Time](s))
+		})
+		if tb.failed || !replaceNow().Equal(fixed) || !s.Called() {
+			t.Error("expected variable to be replaced with stub function")
+		}
+		tb.finish()
+		if replaceNow().Equal(fixed) {
+			t.Error("expected variable to be restored on cleanup")
+		}
+	})
+
+	t.Run("with invalid targets", func(t *testing.T) {
+		n := 1
+		cases := []struct {
+			target      interface{}
+			replacement interface{}
+			msg         string
+		}{
+			{&n, 2, "pointer to a function variable, got *int"},
+			{replaceNow, time.Now, "pointer to a function variable, got func() time.Time"},
+			{&replaceNow, func() int { return 0 }, "cannot replace func() time.Time with func() int"},
+		}
+		for _, c := range cases {
+			tb := (&fakeTB{name: "TestA"}).run(func(tb testing.TB) {
+				Replace(tb, c.target, c.replacement)
+			})
+			if !tb.failed || !strings.Contains(tb.output[0], c.msg) {
+				t.Errorf("expected failure containing %q, got %v", c.msg, tb.output)
+			}
+			tb.finish()
+		}
+	})
+
+	t.Run("with parallel tests", func(t *testing.T) {
+		a := &fakeTB{name: "TestA/one"}
+		b := &fakeTB{name: "TestA/two"}
+		a.run(func(tb testing.TB) { Replace(tb, &replaceNow, time.Now) })
+		b.run(func(tb testing.TB) { Replace(tb, &replaceNow, time.Now) })
+		if a.failed || !b.failed || !strings.Contains(b.output[0], "already replaced by TestA/one") {
+			t.Errorf("expected second test to fail, got %v", b.output)
+		}
+		a.finish()
+		b.finish()
+		c := (&fakeTB{name: "TestA/three"}).run(func(tb testing.TB) {
+			Replace(tb, &replaceNow, time.Now)
+		})
+		if c.failed {
+			t.Error("expected replace to succeed after the first test finished")
+		}
+		c.finish()
+	})
+
+	t.Run("with subtests", func(t *testing.T) {
+		parent := &fakeTB{name: "TestA"}
+		child := &fakeTB{name: "TestA/child"}
+		var calls []string
+		parent.run(func(tb testing.TB) {
+			Replace(tb, &replaceNow, time.Now)
+			Replace(tb, &replaceNow, time.Now)
+		})
+		child.run(func(tb testing.TB) {
+			s := New()
+			s.Does(func(args ...interface{}) []interface{} {
+				calls = append(calls, "child")
+				return nil
+			})
+			Replace(tb, &replaceNow, Func[func() time.Time](s))
+		})
+		if parent.failed || child.failed {
+			t.Error("expected a test and its subtests to replace the same variable")
+		}
+		replaceNow()
+		child.finish()
+		parent.finish()
+		if len(calls) != 1 || replaceNow().IsZero() {
+			t.Error("expected replacements to be restored in order")
+		}
+	})
+}
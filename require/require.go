@@ -0,0 +1,164 @@
+// Package require provides the assertions of package assert, stopping the test
+// with t.FailNow when one fails.
+package require
+
+import (
+	"testing"
+
+	"github.com/brentburg/stubzero"
+	"github.com/brentburg/stubzero/assert"
+)
+
+func Called(t testing.TB, s *stubzero.Stub) {
+	t.Helper()
+	if !assert.Called(t, s) {
+		t.FailNow()
+	}
+}
+
+func NotCalled(t testing.TB, s *stubzero.Stub) {
+	t.Helper()
+	if !assert.NotCalled(t, s) {
+		t.FailNow()
+	}
+}
+
+func CalledOnce(t testing.TB, s *stubzero.Stub) {
+	t.Helper()
+	if !assert.CalledOnce(t, s) {
+		t.FailNow()
+	}
+}
+
+func CallCount(t testing.TB, s *stubzero.Stub, n int) {
+	t.Helper()
+	if !assert.CallCount(t, s, n) {
+		t.FailNow()
+	}
+}
+
+func CalledAtLeast(t testing.TB, s *stubzero.Stub, n int) {
+	t.Helper()
+	if !assert.CalledAtLeast(t, s, n) {
+		t.FailNow()
+	}
+}
+
+func CalledAtMost(t testing.TB, s *stubzero.Stub, n int) {
+	t.Helper()
+	if !assert.CalledAtMost(t, s, n) {
+		t.FailNow()
+	}
+}
+
+func CalledWithTimes(t testing.TB, s *stubzero.Stub, n int, args ...interface{}) {
+	t.Helper()
+	if !assert.CalledWithTimes(t, s, n, args...) {
+		t.FailNow()
+	}
+}
+
+func CalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.CalledWith(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func CalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.CalledWithExactly(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func AlwaysCalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.AlwaysCalledWith(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func AlwaysCalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.AlwaysCalledWithExactly(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func NeverCalledWith(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.NeverCalledWith(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func NeverCalledWithExactly(t testing.TB, s *stubzero.Stub, args ...interface{}) {
+	t.Helper()
+	if !assert.NeverCalledWithExactly(t, s, args...) {
+		t.FailNow()
+	}
+}
+
+func Returned(t testing.TB, s *stubzero.Stub, vals ...interface{}) {
+	t.Helper()
+	if !assert.Returned(t, s, vals...) {
+		t.FailNow()
+	}
+}
+
+func AlwaysReturned(t testing.TB, s *stubzero.Stub, vals ...interface{}) {
+	t.Helper()
+	if !assert.AlwaysReturned(t, s, vals...) {
+		t.FailNow()
+	}
+}
+
+func Panicked(t testing.TB, s *stubzero.Stub) {
+	t.Helper()
+	if !assert.Panicked(t, s) {
+		t.FailNow()
+	}
+}
+
+func CalledBefore(t testing.TB, s, other *stubzero.Stub) {
+	t.Helper()
+	if !assert.CalledBefore(t, s, other) {
+		t.FailNow()
+	}
+}
+
+func CalledAfter(t testing.TB, s, other *stubzero.Stub) {
+	t.Helper()
+	if !assert.CalledAfter(t, s, other) {
+		t.FailNow()
+	}
+}
+
+func CalledImmediatelyBefore(t testing.TB, s, other *stubzero.Stub) {
+	t.Helper()
+	if !assert.CalledImmediatelyBefore(t, s, other) {
+		t.FailNow()
+	}
+}
+
+func CalledImmediatelyAfter(t testing.TB, s, other *stubzero.Stub) {
+	t.Helper()
+	if !assert.CalledImmediatelyAfter(t, s, other) {
+		t.FailNow()
+	}
+}
+
+func InOrder(t testing.TB, steps ...*stubzero.OrderStep) {
+	t.Helper()
+	if !assert.InOrder(t, steps...) {
+		t.FailNow()
+	}
+}
+
+func InStrictOrder(t testing.TB, steps ...*stubzero.OrderStep) {
+	t.Helper()
+	if !assert.InStrictOrder(t, steps...) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,112 @@
+package require
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/brentburg/stubzero"
+)
+
+type recorder struct {
+	testing.TB
+	failed  bool
+	stopped bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recorder) FailNow() {
+	r.stopped = true
+	runtime.Goexit()
+}
+
+func run(fn func(t testing.TB)) *recorder {
+	r := &recorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r
+}
+
+func TestRequire(t *testing.T) {
+	s, other := stubzero.New(), stubzero.New()
+	s.Call(1, 2)
+	other.Call()
+	panicky := stubzero.New()
+	panicky.PanicsOnce("oops")
+	func() {
+		defer func() { recover() }()
+		panicky.Call()
+	}()
+
+	passing := map[string]func(t testing.TB){
+		"Called":                  func(t testing.TB) { Called(t, s) },
+		"NotCalled":               func(t testing.TB) { NotCalled(t, stubzero.New()) },
+		"CalledOnce":              func(t testing.TB) { CalledOnce(t, s) },
+		"CallCount":               func(t testing.TB) { CallCount(t, s, 1) },
+		"CalledAtLeast":           func(t testing.TB) { CalledAtLeast(t, s, 1) },
+		"CalledAtMost":            func(t testing.TB) { CalledAtMost(t, s, 1) },
+		"CalledWithTimes":         func(t testing.TB) { CalledWithTimes(t, s, 1, 1) },
+		"CalledWith":              func(t testing.TB) { CalledWith(t, s, 1) },
+		"CalledWithExactly":       func(t testing.TB) { CalledWithExactly(t, s, 1, 2) },
+		"AlwaysCalledWith":        func(t testing.TB) { AlwaysCalledWith(t, s, 1) },
+		"AlwaysCalledWithExactly": func(t testing.TB) { AlwaysCalledWithExactly(t, s, 1, 2) },
+		"NeverCalledWith":         func(t testing.TB) { NeverCalledWith(t, s, 2) },
+		"NeverCalledWithExactly":  func(t testing.TB) { NeverCalledWithExactly(t, s, 1) },
+		"Returned":                func(t testing.TB) { Returned(t, s) },
+		"AlwaysReturned":          func(t testing.TB) { AlwaysReturned(t, s) },
+		"Panicked":                func(t testing.TB) { Panicked(t, panicky) },
+		"CalledBefore":            func(t testing.TB) { CalledBefore(t, s, other) },
+		"CalledAfter":             func(t testing.TB) { CalledAfter(t, other, s) },
+		"CalledImmediatelyBefore": func(t testing.TB) { CalledImmediatelyBefore(t, s, other) },
+		"CalledImmediatelyAfter":  func(t testing.TB) { CalledImmediatelyAfter(t, other, s) },
+		"InOrder":                 func(t testing.TB) { InOrder(t, stubzero.Called(s), stubzero.Called(other)) },
+		"InStrictOrder":           func(t testing.TB) { InStrictOrder(t, stubzero.Called(s), stubzero.Called(other)) },
+	}
+	failing := map[string]func(t testing.TB){
+		"Called":                  func(t testing.TB) { Called(t, stubzero.New()) },
+		"NotCalled":               func(t testing.TB) { NotCalled(t, s) },
+		"CalledOnce":              func(t testing.TB) { CalledOnce(t, stubzero.New()) },
+		"CallCount":               func(t testing.TB) { CallCount(t, s, 2) },
+		"CalledAtLeast":           func(t testing.TB) { CalledAtLeast(t, s, 2) },
+		"CalledAtMost":            func(t testing.TB) { CalledAtMost(t, s, 0) },
+		"CalledWithTimes":         func(t testing.TB) { CalledWithTimes(t, s, 2, 1) },
+		"CalledWith":              func(t testing.TB) { CalledWith(t, s, 2) },
+		"CalledWithExactly":       func(t testing.TB) { CalledWithExactly(t, s, 1) },
+		"AlwaysCalledWith":        func(t testing.TB) { AlwaysCalledWith(t, s, 2) },
+		"AlwaysCalledWithExactly": func(t testing.TB) { AlwaysCalledWithExactly(t, s, 1) },
+		"NeverCalledWith":         func(t testing.TB) { NeverCalledWith(t, s, 1) },
+		"NeverCalledWithExactly":  func(t testing.TB) { NeverCalledWithExactly(t, s, 1, 2) },
+		"Returned":                func(t testing.TB) { Returned(t, s, 1) },
+		"AlwaysReturned":          func(t testing.TB) { AlwaysReturned(t, s, 1) },
+		"Panicked":                func(t testing.TB) { Panicked(t, s) },
+		"CalledBefore":            func(t testing.TB) { CalledBefore(t, other, s) },
+		"CalledAfter":             func(t testing.TB) { CalledAfter(t, s, other) },
+		"CalledImmediatelyBefore": func(t testing.TB) { CalledImmediatelyBefore(t, other, s) },
+		"CalledImmediatelyAfter":  func(t testing.TB) { CalledImmediatelyAfter(t, s, other) },
+		"InOrder":                 func(t testing.TB) { InOrder(t, stubzero.Called(other), stubzero.Called(s)) },
+		"InStrictOrder":           func(t testing.TB) { InStrictOrder(t, stubzero.Called(other), stubzero.Called(s)) },
+	}
+
+	for name, fn := range passing {
+		if r := run(fn); r.failed || r.stopped {
+			t.Errorf("%s: expected to pass", name)
+		}
+	}
+	for name, fn := range failing {
+		if r := run(fn); !r.failed || !r.stopped {
+			t.Errorf("%s: expected to fail and stop the test", name)
+		}
+	}
+}
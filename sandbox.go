@@ -0,0 +1,159 @@
+package stubzero
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// Sandbox groups named stubs and replaced variables so they can be reset,
+// restored and queried together.
+type Sandbox struct {
+	mu       sync.Mutex
+	names    []string
+	stubs    map[string]*Stub
+	restores []func()
+	opts     []Option
+}
+
+// NamedCall is a call recorded by one of a sandbox's stubs.
+type NamedCall struct {
+	*Call
+	Name string
+}
+
+// NewSandbox returns an empty sandbox whose stubs are created with opts and
+// named after their name in the sandbox.
+func NewSandbox(opts ...Option) *Sandbox {
+	return &Sandbox{stubs: make(map[string]*Stub), opts: opts}
+}
+
+// Stub returns the sandbox's stub with the given name, creating it on first
+// use.
+func (sb *Sandbox) Stub(name string) *Stub {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if s, ok := sb.stubs[name]; ok {
+		return s
+	}
+	s := NewNamed(name, sb.opts...)
+	sb.names = append(sb.names, name)
+	sb.stubs[name] = s
+	return s
+}
+
+func (sb *Sandbox) Reset() {
+	_, stubs := sb.all()
+	for _, s := range stubs {
+		s.Reset()
+	}
+}
+
+func (sb *Sandbox) ResetHistory() {
+	_, stubs := sb.all()
+	for _, s := range stubs {
+		s.ResetHistory()
+	}
+}
+
+// Replace sets the variable target points to to replacement until Restore is
+// called.
+func (sb *Sandbox) Replace(target, replacement interface{}) error {
+	restore, err := replace(target, replacement)
+	if err != nil {
+		return err
+	}
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.restores = append(sb.restores, restore)
+	return nil
+}
+
+// Restore sets replaced variables back to their original values, most
+// recently replaced first.
+func (sb *Sandbox) Restore() {
+	sb.mu.Lock()
+	restores := sb.restores
+	sb.restores = nil
+	sb.mu.Unlock()
+	for i := len(restores) - 1; i >= 0; i-- {
+		restores[i]()
+	}
+}
+
+// Cleanup registers Restore and Reset to run when t and its subtests finish,
+// after which calls to strict stubs of the sandbox panic.
+func (sb *Sandbox) Cleanup(t testing.TB) {
+	t.Cleanup(func() {
+		sb.Restore()
+		sb.Reset()
+		_, stubs := sb.all()
+		for _, s := range stubs {
+			s.finish("sandbox")
+		}
+	})
+}
+
+// NotCalled returns the names of stubs that were never called, in the order
+// they were created.
+func (sb *Sandbox) NotCalled() []string {
+	var notCalled []string
+	names, stubs := sb.all()
+	for i, s := range stubs {
+		if s.NotCalled() {
+			notCalled = append(notCalled, names[i])
+		}
+	}
+	return notCalled
+}
+
+// Timeline returns the calls of all of the sandbox's stubs in the order they
+// were made.
+func (sb *Sandbox) Timeline() []NamedCall {
+	var calls []NamedCall
+	names, stubs := sb.all()
+	for i, s := range stubs {
+		for _, c := range s.Calls() {
+			calls = append(calls, NamedCall{Call: c, Name: names[i]})
+		}
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].CalledBefore(calls[j].Call)
+	})
+	return calls
+}
+
+func (sb *Sandbox) all() ([]string, []*Stub) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	names := make([]string, len(sb.names))
+	stubs := make([]*Stub, len(sb.names))
+	for i, name := range sb.names {
+		names[i] = name
+		stubs[i] = sb.stubs[name]
+	}
+	return names, stubs
+}
+
+func replace(target, replacement interface{}) (func(), error) {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return nil, fmt.Errorf("stubzero: replace target must be a non-nil pointer, got %T", target)
+	}
+	v := tv.Elem()
+	rv := reflect.ValueOf(replacement)
+	if replacement == nil {
+		rv = reflect.Zero(v.Type())
+	}
+	if !rv.Type().AssignableTo(v.Type()) {
+		return nil, fmt.Errorf("stubzero: cannot replace %s with %s", v.Type(), rv.Type())
+	}
+	orig := reflect.New(v.Type()).Elem()
+	orig.Set(v)
+	v.Set(rv)
+	return func() {
+		v.Set(orig)
+	}, nil
+}
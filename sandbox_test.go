@@ -0,0 +1,146 @@
+package stubzero
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/clock"
+)
+
+var sandboxNow = func() string { return "real" }
+
+func TestSandboxOptions(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sb := NewSandbox(WithClock(clock.NewFake(start)))
+	sb.Stub("a").Call()
+	if c := sb.Stub("a").LastCall(); !c.Time.Equal(start) {
+		t.Errorf("expected stub to use the sandbox's clock, got %s", c.Time)
+	}
+}
+
+func TestSandboxStub(t *testing.T) {
+	sb := NewSandbox()
+	if sb.Stub("a") != sb.Stub("a") {
+		t.Error("expected the same name to return the same stub")
+	}
+	if sb.Stub("a") == sb.Stub("b") {
+		t.Error("expected different names to return different stubs")
+	}
+	if name := sb.Stub("a").String(); name != "a" {
+		t.Errorf("expected stub to be named a, got %s", name)
+	}
+}
+
+func TestSandboxReset(t *testing.T) {
+	sb := NewSandbox()
+	a, b := sb.Stub("a"), sb.Stub("b")
+	a.Returns(1)
+	b.Returns(2)
+	a.Call()
+	b.Call()
+	sb.Reset()
+	if a.Called() || b.Called() {
+		t.Error("expected calls of all stubs to be reset")
+	}
+	if len(a.Call()) != 0 || len(b.Call()) != 0 {
+		t.Error("expected behaviors of all stubs to be reset")
+	}
+}
+
+func TestSandboxResetHistory(t *testing.T) {
+	sb := NewSandbox()
+	a, b := sb.Stub("a"), sb.Stub("b")
+	a.Returns(1)
+	a.Call()
+	b.Call()
+	sb.ResetHistory()
+	if a.Called() || b.Called() {
+		t.Error("expected calls of all stubs to be reset")
+	}
+	if ret := a.Call(); ret[0].(int) != 1 {
+		t.Error("expected behaviors to be kept")
+	}
+}
+
+func TestSandboxReplace(t *testing.T) {
+	sb := NewSandbox()
+	n := 1
+	if err := sb.Replace(&sandboxNow, func() string { return "fake" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.Replace(&n, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.Replace(&n, 3); err != nil {
+		t.Fatal(err)
+	}
+	if sandboxNow() != "fake" || n != 3 {
+		t.Error("expected variables to be replaced")
+	}
+	sb.Restore()
+	if sandboxNow() != "real" || n != 1 {
+		t.Error("expected variables to be restored to original values")
+	}
+
+	cases := []struct {
+		target      interface{}
+		replacement interface{}
+		msg         string
+	}{
+		{n, 2, "must be a non-nil pointer, got int"},
+		{(*int)(nil), 2, "must be a non-nil pointer"},
+		{&n, "2", "cannot replace int with string"},
+	}
+	for _, c := range cases {
+		err := sb.Replace(c.target, c.replacement)
+		if err == nil || !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("expected error containing %q, got %v", c.msg, err)
+		}
+	}
+}
+
+func TestSandboxCleanup(t *testing.T) {
+	sb := NewSandbox()
+	s := sb.Stub("a")
+	t.Run("with cleanup", func(t *testing.T) {
+		sb.Cleanup(t)
+		sb.Replace(&sandboxNow, func() string { return "fake" })
+		s.Returns(1)
+		s.Call()
+	})
+	if sandboxNow() != "real" {
+		t.Error("expected variables to be restored after the test")
+	}
+	if s.Called() || len(s.Call()) != 0 {
+		t.Error("expected stubs to be reset after the test")
+	}
+}
+
+func TestSandboxNotCalled(t *testing.T) {
+	sb := NewSandbox()
+	sb.Stub("a")
+	sb.Stub("b").Call()
+	sb.Stub("c")
+	if names := sb.NotCalled(); !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("expected a and c to not be called, got %v", names)
+	}
+}
+
+func TestSandboxTimeline(t *testing.T) {
+	sb := NewSandbox()
+	a, b := sb.Stub("a"), sb.Stub("b")
+	a.Call(1)
+	b.Call(2)
+	a.Call(3)
+	calls := sb.Timeline()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(calls))
+	}
+	for i, name := range []string{"a", "b", "a"} {
+		if calls[i].Name != name || !calls[i].CalledWithExactly(i+1) {
+			t.Errorf("expected call %d to be %s", i+1, name)
+		}
+	}
+}
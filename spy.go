@@ -0,0 +1,42 @@
+package stubzero
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Spy returns a function of the same type as fn that calls through to fn and
+// records each call, with its return values, panic and duration, on the
+// returned stub. Use ResetHistory rather than Reset to clear a spy's calls,
+// since Reset also removes the call through to fn.
+func Spy[F any](fn F) (F, *Stub) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		panic(fmt.Errorf("stubzero: Spy expects a non-nil function, got %T", fn))
+	}
+	t := v.Type()
+	s := New()
+	s.Does(func(args ...interface{}) []interface{} {
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			in[i] = spyArg(t, i, arg)
+		}
+		out := v.Call(in)
+		vals := make([]interface{}, len(out))
+		for i, o := range out {
+			vals[i] = o.Interface()
+		}
+		return vals
+	})
+	return Func[F](s), s
+}
+
+func spyArg(t reflect.Type, i int, arg interface{}) reflect.Value {
+	if arg != nil {
+		return reflect.ValueOf(arg)
+	}
+	if t.IsVariadic() && i >= t.NumIn()-1 {
+		return reflect.Zero(t.In(t.NumIn() - 1).Elem())
+	}
+	return reflect.Zero(t.In(i))
+}
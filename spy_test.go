@@ -0,0 +1,106 @@
+package stubzero
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpy(t *testing.T) {
+	t.Run("with results", func(t *testing.T) {
+		fn, s := Spy(func(a, b int) (int, error) {
+			if b == 0 {
+				return 0, errors.New("divide by zero")
+			}
+			return a / b, nil
+		})
+		if n, err := fn(6, 3); n != 2 || err != nil {
+			t.Error("expected spy to return results of the wrapped function")
+		}
+		if _, err := fn(1, 0); err == nil {
+			t.Error("expected spy to return errors of the wrapped function")
+		}
+		if s.CallCount() != 2 || !s.FirstCall().CalledWithExactly(6, 3) {
+			t.Error("expected calls to be recorded")
+		}
+		first, last := s.FirstCall().ReturnValues, s.LastCall().ReturnValues
+		if len(first) != 2 || first[0] != 2 || first[1] != nil {
+			t.Error("expected return values to be recorded")
+		}
+		if last[1].(error).Error() != "divide by zero" {
+			t.Error("expected returned error to be recorded")
+		}
+	})
+
+	t.Run("with variadic and nil args", func(t *testing.T) {
+		fn, s := Spy(func(err error, parts ...string) string {
+			if err != nil {
+				return err.Error()
+			}
+			return strings.Join(parts, ",")
+		})
+		if fn(nil, "a", "b") != "a,b" {
+			t.Error("expected spy to pass args to the wrapped function")
+		}
+		if !s.CalledWithExactly(nil, "a", "b") {
+			t.Error("expected expanded args to be recorded")
+		}
+	})
+
+	t.Run("with panics", func(t *testing.T) {
+		fn, s := Spy(func() { panic("boom") })
+		err := func() (v interface{}) {
+			defer func() { v = recover() }()
+			fn()
+			return nil
+		}()
+		if err != "boom" {
+			t.Error("expected spy to propagate panics")
+		}
+		if c := s.LastCall(); !c.Panicked || c.PanicValue != "boom" {
+			t.Error("expected panic to be recorded")
+		}
+	})
+
+	t.Run("with duration", func(t *testing.T) {
+		fn, s := Spy(func() { time.Sleep(10 * time.Millisecond) })
+		fn()
+		if s.LastCall().Duration < 10*time.Millisecond {
+			t.Error("expected duration of the call to be recorded")
+		}
+	})
+
+	t.Run("with ordering queries", func(t *testing.T) {
+		open, openSpy := Spy(func(string) {})
+		closeFn, closeSpy := Spy(func() {})
+		open("file")
+		closeFn()
+		if !openSpy.CalledBefore(closeSpy) || !closeSpy.CalledAfter(openSpy) {
+			t.Error("expected spies to support ordering queries")
+		}
+		if !openSpy.NthCall(1).CalledWith("file") {
+			t.Error("expected spies to support call queries")
+		}
+	})
+
+	t.Run("with reset history", func(t *testing.T) {
+		fn, s := Spy(func() int { return 1 })
+		fn()
+		s.ResetHistory()
+		if s.Called() {
+			t.Error("expected history to be cleared")
+		}
+		if fn() != 1 {
+			t.Error("expected spy to still call through")
+		}
+	})
+
+	t.Run("with a nil function", func(t *testing.T) {
+		var fn func()
+		err := recoverError(func() { Spy(fn) })
+		if err == nil || !strings.Contains(err.Error(), "non-nil function") {
+			t.Errorf("expected a descriptive error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package stubzero
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Strict makes the stub report calls it was not configured for to t with
+// t.Errorf. A strict stub accepts a call only if a behavior has a response
+// for it: a matching WithArgs behavior, or the stub itself when none of its
+// WithArgs behaviors has a response. A strict stub with nothing configured,
+// or whose responses have all been used, rejects every call. A maximum set
+// with MaxCalls, on the stub or a WithArgs behavior, may not be exceeded.
+// Calls made after t, or the sandbox the stub belongs to, has finished panic,
+// as t can no longer report them.
+func Strict(t testing.TB) Option {
+	return func(s *Stub) {
+		s.strict = true
+		s.strictT = t
+		t.Cleanup(func() {
+			s.finish("test")
+		})
+	}
+}
+
+// StrictPanics is like Strict, but panics on calls the stub was not
+// configured for.
+func StrictPanics() Option {
+	return func(s *Stub) {
+		s.strict = true
+	}
+}
+
+// MaxCalls sets how many times a strict stub may be called, or called with the
+// behavior's args when set on a WithArgs behavior.
+func (b *Behavior) MaxCalls(n int) {
+	b.stub.mu.Lock()
+	defer b.stub.mu.Unlock()
+	b.maxCalls = n
+	b.limited = true
+}
+
+// finish marks the stub's test or sandbox as finished, after which calls to a
+// strict stub panic.
+func (s *Stub) finish(what string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished == "" {
+		s.finished = what
+	}
+}
+
+// check returns the error for a call a strict stub was not configured for, and
+// whether it should panic rather than be reported.
+func (s *Stub) check(c *Call) (fatal bool, err error) {
+	if !s.strict {
+		return false, nil
+	}
+	if s.finished != "" {
+		return true, fmt.Errorf("stubzero: %s, but the %s has finished", c, s.finished)
+	}
+	err = s.violation(c)
+	return err != nil && s.strictT == nil, err
+}
+
+func (s *Stub) violation(c *Call) error {
+	if s.Behavior.limited && s.Behavior.callCount > s.Behavior.maxCalls {
+		return fmt.Errorf("stubzero: %s, but it may be called at most %s", c, FormatTimes(s.Behavior.maxCalls))
+	}
+	configured, matched := false, false
+	for _, b := range s.behaviors {
+		if b.responder() != nil {
+			configured = true
+		}
+		if !b.matches(c.Args) {
+			continue
+		}
+		if b.responder() != nil {
+			matched = true
+		}
+		if b.limited && b.callCount > b.maxCalls {
+			return fmt.Errorf(
+				"stubzero: %s, but it may be called with %s at most %s",
+				c, FormatArgs(b.args), FormatTimes(b.maxCalls),
+			)
+		}
+	}
+	switch {
+	case configured && !matched:
+		return fmt.Errorf("stubzero: %s, but no WithArgs behavior matches its args", c)
+	case !configured && s.Behavior.responder() == nil:
+		return fmt.Errorf("stubzero: %s, but the stub has nothing configured", c)
+	}
+	return nil
+}
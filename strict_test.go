@@ -0,0 +1,186 @@
+package stubzero
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	t.Run("with WithArgs behaviors", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := NewNamed("repo.Save", Strict(tb))
+		s.Returns(0)
+		s.WithArgs("a").Returns(1)
+		if vals := s.Call("a", 2); vals[0] != 1 || tb.failed {
+			t.Errorf("expected matching call to be accepted, got %v, %v", vals, tb.output)
+		}
+		if vals := s.Call("b"); vals[0] != 0 {
+			t.Errorf("expected unmatched call to still return the stub's values, got %v", vals)
+		}
+		want := `stubzero: repo.Save call 2: ("b"), but no WithArgs behavior matches its args`
+		if len(tb.output) != 1 || tb.output[0] != want {
+			t.Errorf("expected %q, got %v", want, tb.output)
+		}
+	})
+
+	t.Run("without WithArgs behaviors", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.Returns(nil)
+		s.Call("anything")
+		if tb.failed {
+			t.Errorf("expected calls with any args to be accepted, got %v", tb.output)
+		}
+	})
+
+	t.Run("with nothing configured", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.WithArgs("a").MaxCalls(1)
+		s.Call("a")
+		s.ReturnsOnce(1)
+		s.Call("b")
+		s.Call("c")
+		want := []string{
+			`stubzero: stub call 1: ("a"), but the stub has nothing configured`,
+			`stubzero: stub call 3: ("c"), but the stub has nothing configured`,
+		}
+		if fmt.Sprint(tb.output) != fmt.Sprint(want) {
+			t.Errorf("expected %q, got %q", want, tb.output)
+		}
+	})
+
+	t.Run("after Reset", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.WithArgs("a").Returns(1)
+		s.Reset()
+		s.Call("a")
+		want := `stubzero: stub call 1: ("a"), but the stub has nothing configured`
+		if len(tb.output) != 1 || tb.output[0] != want {
+			t.Errorf("expected %q, got %v", want, tb.output)
+		}
+		s.WithArgs("b").Returns(2)
+		s.Call("a")
+		want = `stubzero: stub call 2: ("a"), but no WithArgs behavior matches its args`
+		if len(tb.output) != 2 || tb.output[1] != want {
+			t.Errorf("expected %q, got %v", want, tb.output)
+		}
+	})
+
+	t.Run("with MaxCalls", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.Returns(nil)
+		s.MaxCalls(2)
+		s.Call()
+		s.Call()
+		if tb.failed {
+			t.Fatalf("expected calls up to the maximum to be accepted, got %v", tb.output)
+		}
+		s.Call()
+		want := "stubzero: stub call 3: (), but it may be called at most 2 times"
+		if len(tb.output) != 1 || tb.output[0] != want {
+			t.Errorf("expected %q, got %v", want, tb.output)
+		}
+		s.ResetHistory()
+		s.Call()
+		if len(tb.output) != 1 {
+			t.Errorf("expected ResetHistory to restart the count, got %v", tb.output)
+		}
+	})
+
+	t.Run("with MaxCalls on a WithArgs behavior", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.WithArgs("a").MaxCalls(1)
+		s.WithArgs().Returns(nil)
+		s.Call("a")
+		s.Call("b")
+		s.Call("a")
+		want := `stubzero: stub call 3: ("a"), but it may be called with ("a") at most 1 time`
+		if len(tb.output) != 1 || tb.output[0] != want {
+			t.Errorf("expected %q, got %v", want, tb.output)
+		}
+	})
+
+	t.Run("after the test finished", func(t *testing.T) {
+		tb := &fakeTB{}
+		s := New(Strict(tb))
+		s.Returns(nil)
+		s.Call()
+		tb.finish()
+		v := callAndRecover(s)
+		if err, ok := v.(error); !ok || err.Error() != "stubzero: stub call 2: (), but the test has finished" {
+			t.Errorf("expected call to panic, got %v", v)
+		}
+		if tb.failed {
+			t.Errorf("expected nothing reported to the finished test, got %v", tb.output)
+		}
+		if c := s.LastCall(); !c.Panicked || c.Action != "Strict" {
+			t.Errorf("expected call to be recorded as panicking, got %+v", c)
+		}
+	})
+
+	t.Run("without strict mode", func(t *testing.T) {
+		s := New()
+		s.WithArgs("a").MaxCalls(1)
+		s.Call("a")
+		s.Call("a")
+		s.Call("b")
+		if v := callAndRecover(s); v != nil {
+			t.Errorf("expected non-strict stub to accept all calls, got %v", v)
+		}
+	})
+}
+
+func TestStrictPanics(t *testing.T) {
+	s := New(StrictPanics())
+	s.WithArgs("a").Returns(1)
+	if v := callAndRecover(s, "a"); v != nil {
+		t.Fatalf("expected matching call to be accepted, got %v", v)
+	}
+	v := callAndRecover(s, "b")
+	err, ok := v.(error)
+	if !ok || !strings.Contains(err.Error(), "no WithArgs behavior matches") {
+		t.Fatalf("expected unmatched call to panic, got %v", v)
+	}
+
+	t.Run("keeps values for accepted calls", func(t *testing.T) {
+		s := New(StrictPanics())
+		s.ReturnsOnce(1)
+		s.ReturnsOnce(2)
+		s.MaxCalls(1)
+		s.Call()
+		if v := callAndRecover(s); v == nil {
+			t.Fatal("expected second call to panic")
+		}
+		s.MaxCalls(3)
+		if vals := s.Call(); len(vals) != 1 || vals[0] != 2 {
+			t.Errorf("expected rejected call to leave ReturnsOnce values, got %v", vals)
+		}
+	})
+
+	t.Run("after Reset", func(t *testing.T) {
+		s.Reset()
+		s.MaxCalls(0)
+		if v := callAndRecover(s, "b"); v == nil {
+			t.Error("expected Reset to keep strict mode")
+		}
+	})
+}
+
+func TestSandboxStrict(t *testing.T) {
+	tb := &fakeTB{}
+	sb := NewSandbox(StrictPanics())
+	sb.Cleanup(tb)
+	s := sb.Stub("repo.Save")
+	s.Returns(nil)
+	s.Call()
+	tb.finish()
+	v := callAndRecover(s)
+	if fmt.Sprint(v) != "stubzero: repo.Save call 1: (), but the sandbox has finished" {
+		t.Errorf("expected call after cleanup to panic, got %v", v)
+	}
+}
@@ -1,48 +1,247 @@
 package stubzero
 
 import (
-	"container/list"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/brentburg/stubzero/clock"
 )
 
 type Stub struct {
-	calls         *list.List
-	returns       *list.List
-	defaultReturn []interface{}
+	Behavior
+	name       string
+	mu         sync.Mutex
+	calls      []*Call
+	behaviors  []*Behavior
+	expected   []*Expectation
+	unexpected []*Call
+	failFast   testing.TB
+	notify     chan struct{}
+	gate       *Gate
+	clock      clock.Clock
+	dist       Distribution
+	distCtx    bool
+	strict     bool
+	strictT    testing.TB
+	finished   string
+
+	inFlight    int
+	maxInFlight int
+}
+
+// Option configures a stub created by New.
+type Option func(*Stub)
+
+// WithName names the stub in String, Call.String and failure messages.
+func WithName(name string) Option {
+	return func(s *Stub) {
+		s.name = name
+	}
 }
 
-func New() *Stub {
-	s := &Stub{
-		calls:   list.New(),
-		returns: list.New(),
+// WithClock sets the clock used to time calls. It defaults to clock.Real.
+func WithClock(c clock.Clock) Option {
+	return func(s *Stub) {
+		s.clock = c
+	}
+}
+
+func New(opts ...Option) *Stub {
+	s := &Stub{calls: make([]*Call, 0), clock: clock.Real()}
+	s.Behavior = *newBehavior(s)
+	for _, opt := range opts {
+		opt(s)
 	}
-	s.Reset()
 	return s
 }
 
+// NewNamed returns a new stub with the given name, such as "repo.Save".
+func NewNamed(name string, opts ...Option) *Stub {
+	return New(append([]Option{WithName(name)}, opts...)...)
+}
+
+func (s *Stub) Name() string {
+	return s.name
+}
+
+// String returns the stub's name, or "stub" if it has none.
+func (s *Stub) String() string {
+	if s.name == "" {
+		return "stub"
+	}
+	return s.name
+}
+
 func (s *Stub) Reset() {
-	s.calls.Init()
-	s.returns.Init()
-	s.defaultReturn = make([]interface{}, 0)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = make([]*Call, 0)
+	s.Behavior.reset()
+	for _, b := range s.behaviors {
+		b.reset()
+	}
+	s.expected = nil
+	s.unexpected = nil
+	s.failFast = nil
+	if s.gate != nil {
+		s.gate.openLocked()
+		s.gate = nil
+	}
+	s.dist = nil
+	s.maxInFlight = s.inFlight
+}
+
+// ResetHistory clears recorded calls but keeps configured behaviors, which
+// start counting calls for OnCall from 1 again.
+func (s *Stub) ResetHistory() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = make([]*Call, 0)
+	s.Behavior.callCount = 0
+	for _, b := range s.behaviors {
+		b.callCount = 0
+	}
+	for _, e := range s.expected {
+		e.count = 0
+	}
+	s.unexpected = nil
+	s.maxInFlight = s.inFlight
 }
 
 func (s *Stub) Call(args ...interface{}) []interface{} {
-	s.calls.PushBack(newCall(args...))
-	if s.returns.Len() > 0 {
-		return s.returns.Remove(s.returns.Front()).([]interface{})
+	s.mu.Lock()
+	c := newCall(args...)
+	c.Time = s.clock.Now()
+	c.stub = s
+	s.calls = append(s.calls, c)
+	c.index = len(s.calls)
+	s.wake()
+	unexpected := s.expect(c)
+	b := s.responder(args)
+	fatal, err := s.check(c)
+	var r *response
+	if fatal {
+		b, r = nil, &response{action: "Strict", does: panicWith(err)}
+	} else {
+		r = b.next()
+	}
+	if r.action != "" {
+		c.Behavior = b
+		c.Action = r.action
+	}
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	g, after := s.gate, s.hold(r)
+	failFast, strictT := s.failFast, s.strictT
+	s.mu.Unlock()
+	// Failures are reported without the lock held, as t.Errorf panics when
+	// the test has finished.
+	report := func() {
+		if unexpected && failFast != nil {
+			failFast.Errorf("%s", unexpectedCall(c))
+		}
+		if err != nil && !fatal {
+			strictT.Errorf("%v", err)
+		}
 	}
-	return s.defaultReturn
+	return s.respond(c, r, g, after, report)
 }
 
-func (s *Stub) ReturnsOnce(vals ...interface{}) {
-	s.returns.PushBack(vals)
+func (s *Stub) respond(c *Call, r *response, g *Gate, after delay, report func()) (vals []interface{}) {
+	start := s.clock.Now()
+	panicked := true
+	defer func() {
+		var v interface{}
+		if panicked {
+			v = recover()
+			// A nil value means the call ran runtime.Goexit, such as a Does
+			// calling t.FailNow, which is left to unwind the goroutine.
+			panicked = v != nil
+		}
+		s.mu.Lock()
+		s.inFlight--
+		c.ReturnValues = vals
+		c.Panicked = panicked
+		c.PanicValue = v
+		c.Duration = s.clock.Since(start)
+		c.finished = true
+		s.mu.Unlock()
+		if panicked {
+			panic(v)
+		}
+	}()
+	report()
+	var err error
+	if g != nil {
+		err = g.wait(c.Args)
+	}
+	if err == nil {
+		err = s.sleep(after, c.Args)
+	}
+	vals = r.respond(c.Args)
+	if err != nil {
+		vals = withErr(vals, err)
+	}
+	panicked = false
+	return vals
 }
 
-func (s *Stub) Returns(vals ...interface{}) {
-	s.defaultReturn = vals
+// WithArgs returns the behavior used for calls whose leading arguments match
+// args, compared with match.Match. Calling it again with deeply equal args
+// returns the same behavior. When several configured behaviors match a call,
+// the one with the most args wins and ties go to the most recently added.
+// Behaviors with nothing left to return are skipped, falling back to the
+// stub's own values.
+//
+// Each call is answered by the first of these that is configured:
+//  1. the winning WithArgs behavior's OnCall, once queue, then default
+//  2. the stub's OnCall, once queue, then default
+//
+// The once queue holds ReturnsOnce, DoesOnce and PanicsOnce values in the
+// order they were added; the default is the most recent Returns,
+// ReturnsAfter, Does or Panics.
+func (s *Stub) WithArgs(args ...interface{}) *Behavior {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, b := range s.behaviors {
+		if reflect.DeepEqual(b.args, args) {
+			return b
+		}
+	}
+	b := newBehavior(s, args...)
+	s.behaviors = append(s.behaviors, b)
+	return b
+}
+
+func (s *Stub) responder(args []interface{}) *Behavior {
+	s.Behavior.callCount++
+	var best, resp *Behavior
+	for _, b := range s.behaviors {
+		if !b.matches(args) {
+			continue
+		}
+		b.callCount++
+		r := b.responder()
+		if r != nil && (best == nil || len(b.args) >= len(best.args)) {
+			best, resp = b, r
+		}
+	}
+	if resp != nil {
+		return resp
+	}
+	if r := s.Behavior.responder(); r != nil {
+		return r
+	}
+	return &s.Behavior
 }
 
 func (s *Stub) CallCount() int {
-	return s.calls.Len()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
 }
 
 func (s *Stub) Called() bool {
@@ -57,45 +256,103 @@ func (s *Stub) CalledOnce() bool {
 	return s.CallCount() == 1
 }
 
+func (s *Stub) CalledTimes(n int) bool {
+	return s.CallCount() == n
+}
+
+func (s *Stub) CalledTwice() bool {
+	return s.CalledTimes(2)
+}
+
+func (s *Stub) CalledThrice() bool {
+	return s.CalledTimes(3)
+}
+
+func (s *Stub) CalledAtLeast(n int) bool {
+	return s.CallCount() >= n
+}
+
+func (s *Stub) CalledAtMost(n int) bool {
+	return s.CallCount() <= n
+}
+
+func (s *Stub) CallCountWith(args ...interface{}) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, c := range s.calls {
+		if c.CalledWith(args...) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Stub) CalledWithTimes(n int, args ...interface{}) bool {
+	return s.CallCountWith(args...) == n
+}
+
 func (s *Stub) FirstCall() *Call {
 	return s.NthCall(1)
 }
 
 func (s *Stub) NthCall(n int) *Call {
-	if s.CallCount() < n {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 || len(s.calls) < n {
 		return nil
 	}
-	e := s.calls.Front()
-	for i := 1; i < n; i++ {
-		e = e.Next()
-	}
-	return e.Value.(*Call)
+	return s.calls[n-1]
 }
 
 func (s *Stub) LastCall() *Call {
-	if !s.Called() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.calls) == 0 {
 		return nil
 	}
-	return s.calls.Back().Value.(*Call)
+	return s.calls[len(s.calls)-1]
 }
 
 func (s *Stub) CalledBefore(t *Stub) bool {
-	if s.NotCalled() || t.NotCalled() {
+	first, last := s.FirstCall(), t.LastCall()
+	if first == nil || last == nil {
 		return false
 	}
-	return s.FirstCall().CalledBefore(t.LastCall())
+	return first.CalledBefore(last)
 }
 
 func (s *Stub) CalledAfter(t *Stub) bool {
-	if s.NotCalled() || t.NotCalled() {
+	last, first := s.LastCall(), t.FirstCall()
+	if last == nil || first == nil {
+		return false
+	}
+	return last.CalledAfter(first)
+}
+
+// CalledImmediatelyBefore reports whether the last call of s was made just
+// before the last call of t, with no call on any stub in between.
+func (s *Stub) CalledImmediatelyBefore(t *Stub) bool {
+	last, tLast := s.LastCall(), t.LastCall()
+	if last == nil || tLast == nil {
+		return false
+	}
+	return last.CalledImmediatelyBefore(tLast)
+}
+
+func (s *Stub) CalledImmediatelyAfter(t *Stub) bool {
+	last, tLast := s.LastCall(), t.LastCall()
+	if last == nil || tLast == nil {
 		return false
 	}
-	return s.LastCall().CalledAfter(t.FirstCall())
+	return last.CalledImmediatelyAfter(tLast)
 }
 
 func (s *Stub) CalledWith(args ...interface{}) bool {
-	for e := s.calls.Front(); e != nil; e = e.Next() {
-		if e.Value.(*Call).CalledWith(args...) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.CalledWith(args...) {
 			return true
 		}
 	}
@@ -103,8 +360,10 @@ func (s *Stub) CalledWith(args ...interface{}) bool {
 }
 
 func (s *Stub) CalledWithExactly(args ...interface{}) bool {
-	for e := s.calls.Front(); e != nil; e = e.Next() {
-		if e.Value.(*Call).CalledWithExactly(args...) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.CalledWithExactly(args...) {
 			return true
 		}
 	}
@@ -112,8 +371,10 @@ func (s *Stub) CalledWithExactly(args ...interface{}) bool {
 }
 
 func (s *Stub) AlwaysCalledWith(args ...interface{}) bool {
-	for e := s.calls.Front(); e != nil; e = e.Next() {
-		if !e.Value.(*Call).CalledWith(args...) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if !c.CalledWith(args...) {
 			return false
 		}
 	}
@@ -121,14 +382,55 @@ func (s *Stub) AlwaysCalledWith(args ...interface{}) bool {
 }
 
 func (s *Stub) AlwaysCalledWithExactly(args ...interface{}) bool {
-	for e := s.calls.Front(); e != nil; e = e.Next() {
-		if !e.Value.(*Call).CalledWithExactly(args...) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if !c.CalledWithExactly(args...) {
 			return false
 		}
 	}
 	return true
 }
 
+// Returned reports whether any call returned values matching vals exactly,
+// compared with match.Match.
+func (s *Stub) Returned(vals ...interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.returned(vals) {
+			return true
+		}
+	}
+	return false
+}
+
+// AlwaysReturned reports whether every finished call returned values matching
+// vals exactly. Calls that panicked did not return; calls still in progress
+// are ignored.
+func (s *Stub) AlwaysReturned(vals ...interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.finished && !c.returned(vals) {
+			return false
+		}
+	}
+	return true
+}
+
+// Panicked reports whether any call panicked.
+func (s *Stub) Panicked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.Panicked {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Stub) NeverCalledWith(args ...interface{}) bool {
 	return !s.CalledWith(args...)
 }
@@ -1,15 +1,21 @@
 package stubzero
 
 import (
+	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/clock"
+	"github.com/brentburg/stubzero/match"
 )
 
 func TestNew(t *testing.T) {
 	s := New()
-	if s.calls == nil || s.calls.Len() > 0 {
+	if s.calls == nil || len(s.calls) > 0 {
 		t.Error("calls list is not initialized as an empty list")
 	}
-	if s.returns == nil || s.calls.Len() > 0 {
+	if s.returns == nil || len(s.calls) > 0 {
 		t.Error("returns list is not initializes as an empty list")
 	}
 	if s.defaultReturn == nil || len(s.defaultReturn) > 0 {
@@ -17,16 +23,48 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewNamed(t *testing.T) {
+	s := NewNamed("repo.Save")
+	if s.Name() != "repo.Save" || s.String() != "repo.Save" {
+		t.Errorf("expected stub named repo.Save, got %q", s)
+	}
+	if s := New(); s.Name() != "" || s.String() != "stub" {
+		t.Errorf("expected unnamed stub to render as stub, got %q", s)
+	}
+	if s := New(WithName("repo.Load")); s.String() != "repo.Load" {
+		t.Errorf("expected WithName to name the stub, got %q", s)
+	}
+}
+
+func TestNewWithClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	s := New(WithClock(fake))
+	s.Does(func(args ...interface{}) []interface{} {
+		fake.Advance(3 * time.Second)
+		return nil
+	})
+	s.Call()
+	s.Call()
+	first, second := s.FirstCall(), s.LastCall()
+	if !first.Time.Equal(start) || !second.Time.Equal(start.Add(3*time.Second)) {
+		t.Errorf("expected call times from the fake clock, got %s and %s", first.Time, second.Time)
+	}
+	if first.Duration != 3*time.Second {
+		t.Errorf("expected duration of 3s, got %s", first.Duration)
+	}
+}
+
 func TestStubReset(t *testing.T) {
 	s := New()
 	s.ReturnsOnce(1)
 	s.Returns(2)
 	s.Call(1, 2)
 	s.Reset()
-	if s.calls == nil || s.calls.Len() > 0 {
+	if s.calls == nil || len(s.calls) > 0 {
 		t.Error("calls list is not reset to an empty list")
 	}
-	if s.returns == nil || s.calls.Len() > 0 {
+	if s.returns == nil || len(s.calls) > 0 {
 		t.Error("returns list is not reset to an empty list")
 	}
 	if s.defaultReturn == nil || len(s.defaultReturn) > 0 {
@@ -38,7 +76,7 @@ func TestStubCall(t *testing.T) {
 	s := New()
 	s.Call()
 	s.Call()
-	if s.calls.Len() != 2 {
+	if len(s.calls) != 2 {
 		t.Error("stub expected to have recorded 2 calls")
 	}
 }
@@ -125,6 +163,116 @@ func TestStubCalledOnce(t *testing.T) {
 	}
 }
 
+func TestStubCalledTimes(t *testing.T) {
+	s := New()
+	if !s.CalledTimes(0) {
+		t.Error("expected called 0 times to be true when not called")
+	}
+	s.Call()
+	s.Call()
+	if !s.CalledTimes(2) {
+		t.Error("expected called 2 times to be true")
+	}
+	if s.CalledTimes(1) || s.CalledTimes(3) {
+		t.Error("expected called times to be false for other counts")
+	}
+}
+
+func TestStubCalledTwice(t *testing.T) {
+	s := New()
+	s.Call()
+	if s.CalledTwice() {
+		t.Error("expected called twice to be false when called once")
+	}
+	s.Call()
+	if !s.CalledTwice() {
+		t.Error("expected called twice to be true")
+	}
+	s.Call()
+	if s.CalledTwice() {
+		t.Error("expected called twice to be false when called more than twice")
+	}
+}
+
+func TestStubCalledThrice(t *testing.T) {
+	s := New()
+	s.Call()
+	s.Call()
+	if s.CalledThrice() {
+		t.Error("expected called thrice to be false when called twice")
+	}
+	s.Call()
+	if !s.CalledThrice() {
+		t.Error("expected called thrice to be true")
+	}
+	s.Call()
+	if s.CalledThrice() {
+		t.Error("expected called thrice to be false when called more than thrice")
+	}
+}
+
+func TestStubCalledAtLeast(t *testing.T) {
+	s := New()
+	if !s.CalledAtLeast(0) {
+		t.Error("expected called at least 0 times to be true")
+	}
+	s.Call()
+	s.Call()
+	if !s.CalledAtLeast(2) || !s.CalledAtLeast(1) {
+		t.Error("expected called at least 2 times to be true")
+	}
+	if s.CalledAtLeast(3) {
+		t.Error("expected called at least 3 times to be false")
+	}
+}
+
+func TestStubCalledAtMost(t *testing.T) {
+	s := New()
+	s.Call()
+	s.Call()
+	if !s.CalledAtMost(2) || !s.CalledAtMost(3) {
+		t.Error("expected called at most 2 times to be true")
+	}
+	if s.CalledAtMost(1) {
+		t.Error("expected called at most 1 time to be false")
+	}
+}
+
+func TestStubCallCountWith(t *testing.T) {
+	s := New()
+	if s.CallCountWith(1) != 0 {
+		t.Error("expected call count with 1 to be 0")
+	}
+	s.Call(1, 2)
+	s.Call(1, 3)
+	s.Call(2)
+	if s.CallCountWith(1) != 2 {
+		t.Error("expected call count with 1 to be 2")
+	}
+	if s.CallCountWith(1, 3) != 1 {
+		t.Error("expected call count with 1, 3 to be 1")
+	}
+	if s.CallCountWith(match.Any) != 3 {
+		t.Error("expected call count with matcher to be 3")
+	}
+}
+
+func TestStubCalledWithTimes(t *testing.T) {
+	s := New()
+	s.Call("a")
+	s.Call("a", 1)
+	s.Call("b")
+	if !s.CalledWithTimes(2, "a") {
+		t.Error("expected stub to be called with a 2 times")
+	}
+	if s.CalledWithTimes(1, "a") {
+		t.Error("expected stub to not be called with a 1 time")
+	}
+	if !s.CalledWithTimes(1, match.Regexp("^b")) {
+		t.Error("expected stub to be called with matching value 1 time")
+	}
+}
+
 func TestStubFirstCall(t *testing.T) {
 	s := New()
 	if s.FirstCall() != nil {
@@ -203,6 +351,49 @@ func TestStubCalledAfter(t *testing.T) {
 	}
 }
 
+func TestStubCalledImmediatelyBefore(t *testing.T) {
+	s1 := New()
+	s2 := New()
+	s3 := New()
+	if s1.CalledImmediatelyBefore(s2) {
+		t.Error("expected false when stubs were not called")
+	}
+	s1.Call()
+	s2.Call()
+	if !s1.CalledImmediatelyBefore(s2) {
+		t.Error("expected s1 to be called immediately before s2")
+	}
+	s1.Call()
+	s3.Call()
+	s2.Call()
+	if s1.CalledImmediatelyBefore(s2) {
+		t.Error("expected s1 to not be called immediately before s2")
+	}
+	if s2.CalledImmediatelyBefore(s1) {
+		t.Error("expected s2 to not be called immediately before s1")
+	}
+}
+
+func TestStubCalledImmediatelyAfter(t *testing.T) {
+	s1 := New()
+	s2 := New()
+	s3 := New()
+	if s1.CalledImmediatelyAfter(s2) {
+		t.Error("expected false when stubs were not called")
+	}
+	s2.Call()
+	s1.Call()
+	if !s1.CalledImmediatelyAfter(s2) {
+		t.Error("expected s1 to be called immediately after s2")
+	}
+	s2.Call()
+	s3.Call()
+	s1.Call()
+	if s1.CalledImmediatelyAfter(s2) {
+		t.Error("expected s1 to not be called immediately after s2")
+	}
+}
+
 func TestStubCalledWith(t *testing.T) {
 	s := New()
 	s.Call(1, 2, 3)
@@ -256,6 +447,67 @@ func TestStubAlwaysCalledWithExactly(t *testing.T) {
 	}
 }
 
+func TestStubReturned(t *testing.T) {
+	s := New()
+	s.ReturnsOnce(1)
+	s.Returns(2)
+	s.Call()
+	s.Call()
+	if !s.Returned(1) || !s.Returned(2) {
+		t.Error("expected stub to have returned 1 and 2")
+	}
+	if s.Returned(3) {
+		t.Error("expected stub to not have returned 3")
+	}
+	if v := s.NthCall(2).ReturnValues; !reflect.DeepEqual(v, []interface{}{2}) {
+		t.Errorf("expected second call to return [2], got %v", v)
+	}
+}
+
+func TestStubAlwaysReturned(t *testing.T) {
+	s := New()
+	s.Returns(1)
+	if !s.AlwaysReturned(1) {
+		t.Error("expected true when stub was not called")
+	}
+	s.Call()
+	s.Call()
+	if !s.AlwaysReturned(1) {
+		t.Error("expected stub to always return 1")
+	}
+	s.ReturnsOnce(2)
+	s.Call()
+	if s.AlwaysReturned(1) {
+		t.Error("expected stub to not always return 1")
+	}
+}
+
+func TestStubPanicked(t *testing.T) {
+	s := New()
+	s.PanicsOnce("oops")
+	s.Returns(1)
+	func() {
+		defer func() { recover() }()
+		s.Call()
+	}()
+	s.Call()
+	if !s.Panicked() {
+		t.Error("expected stub to have panicked")
+	}
+	if s.AlwaysReturned(1) {
+		t.Error("expected a call that panicked to not count as returning")
+	}
+	c := s.FirstCall()
+	if !c.Panicked || c.PanicValue != "oops" || c.Action != "PanicsOnce" {
+		t.Errorf("unexpected panic record: %+v", c)
+	}
+	s.ResetHistory()
+	s.Call()
+	if s.Panicked() {
+		t.Error("expected stub to not have panicked after ResetHistory")
+	}
+}
+
 func TestStubNeverCalledWith(t *testing.T) {
 	s := New()
 	s.Call(1, 2)
@@ -282,3 +534,113 @@ func TestStubNeverCalledWithExactly(t *testing.T) {
 		t.Error("expected stub to not be never be called with exactly 3, 4, 5")
 	}
 }
+
+func TestStubConcurrentCall(t *testing.T) {
+	s := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				s.Call(i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.CallCount() != 1000 {
+		t.Errorf("expected call count to be 1000, got %d", s.CallCount())
+	}
+}
+
+func TestStubConcurrentReturnsOnce(t *testing.T) {
+	s := New()
+	s.Returns(-1)
+	for i := 0; i < 500; i++ {
+		s.ReturnsOnce(i)
+	}
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				ret := s.Call()
+				mu.Lock()
+				seen[ret[0].(int)]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 500; i++ {
+		if seen[i] != 1 {
+			t.Errorf("expected one time return %d to be returned once, got %d", i, seen[i])
+		}
+	}
+	if seen[-1] != 500 {
+		t.Errorf("expected default to be returned 500 times, got %d", seen[-1])
+	}
+}
+
+func TestStubConcurrentQueries(t *testing.T) {
+	s := New()
+	other := New()
+	other.Call()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.ReturnsOnce(j)
+				s.Returns(i)
+				s.Call(i, j)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.CallCount()
+				s.Called()
+				s.NotCalled()
+				s.CalledOnce()
+				s.FirstCall()
+				s.NthCall(j)
+				s.LastCall()
+				s.CalledBefore(other)
+				s.CalledAfter(other)
+				other.CalledBefore(s)
+				s.CalledWith(i)
+				s.CalledWithExactly(i, j)
+				s.AlwaysCalledWith(i)
+				s.AlwaysCalledWithExactly(i, j)
+				s.NeverCalledWith(i)
+				s.NeverCalledWithExactly(i, j)
+				if j%25 == 0 {
+					s.Reset()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestStubResetHistory(t *testing.T) {
+	s := New()
+	s.Returns(1)
+	s.OnSecondCall().Returns(2)
+	s.Call()
+	s.ResetHistory()
+	if s.Called() {
+		t.Error("expected calls to be cleared")
+	}
+	if ret := s.Call(); ret[0].(int) != 1 {
+		t.Error("expected behaviors to be kept")
+	}
+	if ret := s.Call(); ret[0].(int) != 2 {
+		t.Error("expected on call numbering to restart")
+	}
+}
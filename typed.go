@@ -0,0 +1,138 @@
+package stubzero
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedStub wraps a Stub with typed arguments and return value. When A is a
+// struct its fields are recorded as separate call arguments, in order, so the
+// untyped queries and WithArgs can match them with values or matchers; any
+// other A is recorded as a single argument. R is returned as a single value.
+type TypedStub[A, R any] struct {
+	*Stub
+}
+
+type TypedBehavior[A, R any] struct {
+	*Behavior
+}
+
+// TypedCall is a Call with its arguments and return value converted back to
+// A and R. Args shadows the untyped Call.Args.
+type TypedCall[A, R any] struct {
+	*Call
+	Args   A
+	Return R
+}
+
+func NewTyped[A, R any](opts ...Option) *TypedStub[A, R] {
+	t := reflect.TypeOf((*A)(nil)).Elem()
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				panic(fmt.Errorf(
+					"stubzero: field %s of %s must be exported to be used as arguments",
+					t.Field(i).Name, t,
+				))
+			}
+		}
+	}
+	return &TypedStub[A, R]{Stub: New(opts...)}
+}
+
+func (s *TypedStub[A, R]) Call(args A) R {
+	return Result[R](s.Stub.Call(flattenArgs(args)...), 0)
+}
+
+func (s *TypedStub[A, R]) Returns(r R) {
+	s.Stub.Returns(r)
+}
+
+func (s *TypedStub[A, R]) ReturnsOnce(r R) {
+	s.Stub.ReturnsOnce(r)
+}
+
+func (s *TypedStub[A, R]) Does(fn func(A) R) {
+	s.Stub.Does(typedDoes(fn))
+}
+
+// WithArgs takes untyped values or matchers for the leading arguments, as
+// Stub.WithArgs does, and returns a behavior with typed return values.
+func (s *TypedStub[A, R]) WithArgs(args ...interface{}) *TypedBehavior[A, R] {
+	return &TypedBehavior[A, R]{s.Stub.WithArgs(args...)}
+}
+
+// CalledWith reports whether the stub was called with arguments deeply equal
+// to args. Use Stub.CalledWith to match individual arguments.
+func (s *TypedStub[A, R]) CalledWith(args A) bool {
+	return s.Stub.CalledWithExactly(flattenArgs(args)...)
+}
+
+func (s *TypedStub[A, R]) FirstCall() *TypedCall[A, R] {
+	return typedCall[A, R](s.Stub.FirstCall())
+}
+
+func (s *TypedStub[A, R]) NthCall(n int) *TypedCall[A, R] {
+	return typedCall[A, R](s.Stub.NthCall(n))
+}
+
+func (s *TypedStub[A, R]) LastCall() *TypedCall[A, R] {
+	return typedCall[A, R](s.Stub.LastCall())
+}
+
+func (b *TypedBehavior[A, R]) Returns(r R) {
+	b.Behavior.Returns(r)
+}
+
+func (b *TypedBehavior[A, R]) ReturnsOnce(r R) {
+	b.Behavior.ReturnsOnce(r)
+}
+
+func (b *TypedBehavior[A, R]) Does(fn func(A) R) {
+	b.Behavior.Does(typedDoes(fn))
+}
+
+func typedDoes[A, R any](fn func(A) R) func(args ...interface{}) []interface{} {
+	return func(args ...interface{}) []interface{} {
+		return []interface{}{fn(unflattenArgs[A](args))}
+	}
+}
+
+func typedCall[A, R any](c *Call) *TypedCall[A, R] {
+	if c == nil {
+		return nil
+	}
+	return &TypedCall[A, R]{
+		Call:   c,
+		Args:   unflattenArgs[A](c.Args),
+		Return: Result[R](c.Result().ReturnValues, 0),
+	}
+}
+
+func flattenArgs[A any](args A) []interface{} {
+	v := reflect.ValueOf(&args).Elem()
+	if v.Kind() != reflect.Struct {
+		return []interface{}{args}
+	}
+	vals := make([]interface{}, v.NumField())
+	for i := range vals {
+		vals[i] = v.Field(i).Interface()
+	}
+	return vals
+}
+
+func unflattenArgs[A any](vals []interface{}) A {
+	var args A
+	v := reflect.ValueOf(&args).Elem()
+	if v.Kind() != reflect.Struct {
+		return Result[A](vals, 0)
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f, err := resultValue(v.Field(i).Type(), vals, i)
+		if err != nil {
+			panic(fmt.Errorf("stubzero: %v", err))
+		}
+		v.Field(i).Set(f)
+	}
+	return args
+}
@@ -0,0 +1,96 @@
+package stubzero
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+type saveArgs struct {
+	ID   string
+	Tags []string
+}
+
+func TestTypedStub(t *testing.T) {
+	t.Run("with struct args", func(t *testing.T) {
+		s := NewTyped[saveArgs, error]()
+		s.ReturnsOnce(errors.New("fail"))
+		if err := s.Call(saveArgs{"a", []string{"x"}}); err == nil || err.Error() != "fail" {
+			t.Error("expected to return one time value")
+		}
+		if err := s.Call(saveArgs{"b", nil}); err != nil {
+			t.Error("expected to return zero value when unconfigured")
+		}
+		if !s.CalledWith(saveArgs{"a", []string{"x"}}) {
+			t.Error("expected stub to be called with args")
+		}
+		if s.CalledWith(saveArgs{"a", nil}) {
+			t.Error("expected stub to not be called with different args")
+		}
+		if !s.Stub.CalledWithExactly("b", []string(nil)) {
+			t.Error("expected struct fields to be recorded as call args")
+		}
+	})
+
+	t.Run("with non-struct args", func(t *testing.T) {
+		s := NewTyped[int, string]()
+		s.Returns("default")
+		if s.Call(1) != "default" || !s.CalledWith(1) {
+			t.Error("expected non-struct args to be a single call arg")
+		}
+	})
+
+	t.Run("with matchers", func(t *testing.T) {
+		s := NewTyped[saveArgs, int]()
+		s.Returns(0)
+		s.WithArgs(match.Regexp("^user-"), match.Contains("admin")).Returns(1)
+		if s.Call(saveArgs{"user-1", []string{"admin"}}) != 1 {
+			t.Error("expected matching behavior to return typed value")
+		}
+		if s.Call(saveArgs{"user-2", nil}) != 0 {
+			t.Error("expected default when matchers do not match")
+		}
+		if !s.Stub.CalledWith(match.Regexp("^user-"), match.Any) {
+			t.Error("expected untyped matchers to work on recorded args")
+		}
+	})
+
+	t.Run("with does", func(t *testing.T) {
+		s := NewTyped[saveArgs, int]()
+		s.Does(func(args saveArgs) int { return len(args.Tags) })
+		s.WithArgs("none").Does(func(args saveArgs) int { return -1 })
+		if s.Call(saveArgs{"a", []string{"x", "y"}}) != 2 {
+			t.Error("expected function to compute value from typed args")
+		}
+		if s.Call(saveArgs{"none", []string{"x"}}) != -1 {
+			t.Error("expected behavior function to compute value")
+		}
+	})
+
+	t.Run("with typed calls", func(t *testing.T) {
+		s := NewTyped[saveArgs, int]()
+		if s.FirstCall() != nil || s.LastCall() != nil {
+			t.Error("expected no calls")
+		}
+		s.ReturnsOnce(1)
+		s.ReturnsOnce(2)
+		s.Call(saveArgs{"a", nil})
+		s.Call(saveArgs{"b", []string{"x"}})
+		first, last := s.FirstCall(), s.NthCall(2)
+		if first.Args.ID != "a" || first.Return != 1 {
+			t.Error("expected first call to have typed args and return")
+		}
+		if last.Args.Tags[0] != "x" || last.Return != 2 || last.Call != s.Stub.LastCall() {
+			t.Error("expected last call to have typed args and return")
+		}
+	})
+
+	t.Run("with unexported fields", func(t *testing.T) {
+		err := recoverError(func() { NewTyped[struct{ id string }, int]() })
+		if err == nil || !strings.Contains(err.Error(), "field id") {
+			t.Errorf("expected a descriptive error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package stubzero
+
+import (
+	"context"
+	"fmt"
+)
+
+// Notify returns a channel that is closed when the stub is next called.
+func (s *Stub) Notify() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notifier()
+}
+
+// WaitForCalls blocks until the stub has been called at least n times or ctx
+// is done. Calls count as soon as they are recorded, before their behavior
+// runs.
+func (s *Stub) WaitForCalls(ctx context.Context, n int) error {
+	err := s.wait(ctx, func() bool { return len(s.calls) >= n })
+	if err != nil {
+		return fmt.Errorf("stubzero: %s was called %s, waiting for %d: %w", s, FormatTimes(s.CallCount()), n, err)
+	}
+	return nil
+}
+
+// WaitForCallWith blocks until the stub has been called with args, compared
+// with match.Match as in CalledWith, or ctx is done. It returns the first
+// matching call.
+func (s *Stub) WaitForCallWith(ctx context.Context, args ...interface{}) (*Call, error) {
+	var call *Call
+	err := s.wait(ctx, func() bool {
+		for _, c := range s.calls {
+			if c.CalledWith(args...) {
+				call = c
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("stubzero: waiting for %s to be called with %s: %w", s, FormatArgs(args), err)
+	}
+	return call, nil
+}
+
+// wait checks done with s.mu held each time the stub is called.
+func (s *Stub) wait(ctx context.Context, done func() bool) error {
+	for {
+		s.mu.Lock()
+		if done() {
+			s.mu.Unlock()
+			return nil
+		}
+		ch := s.notifier()
+		s.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Stub) notifier() chan struct{} {
+	if s.notify == nil {
+		s.notify = make(chan struct{})
+	}
+	return s.notify
+}
+
+func (s *Stub) wake() {
+	if s.notify != nil {
+		close(s.notify)
+		s.notify = nil
+	}
+}
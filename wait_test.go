@@ -0,0 +1,117 @@
+package stubzero
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brentburg/stubzero/match"
+)
+
+func TestStubNotify(t *testing.T) {
+	s := New()
+	ch := s.Notify()
+	select {
+	case <-ch:
+		t.Fatal("expected channel to be open before a call")
+	default:
+	}
+	go s.Call()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("expected channel to be closed by a call")
+	}
+	if !s.Called() {
+		t.Error("expected call to be recorded before notifying")
+	}
+	select {
+	case <-s.Notify():
+		t.Error("expected a new channel to wait for the next call")
+	default:
+	}
+}
+
+func TestStubWaitForCalls(t *testing.T) {
+	s := New()
+	go func() {
+		for i := 0; i < 3; i++ {
+			s.Call(i)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.WaitForCalls(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if s.CallCount() < 3 {
+		t.Errorf("expected 3 calls, got %d", s.CallCount())
+	}
+
+	t.Run("already called", func(t *testing.T) {
+		if err := s.WaitForCalls(context.Background(), 2); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := s.WaitForCalls(ctx, 4)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "stub was called 3 times, waiting for 4") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStubWaitForCallWith(t *testing.T) {
+	s := New()
+	go func() {
+		s.Call("a", 1)
+		s.Call("b", 2)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c, err := s.WaitForCallWith(ctx, "b", match.Any)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.CalledWithExactly("b", 2) {
+		t.Errorf("expected call with b, 2, got %v", c.Args)
+	}
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		c, err := s.WaitForCallWith(ctx, "c")
+		if c != nil || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v, %v", c, err)
+		}
+		if !strings.Contains(err.Error(), `waiting for stub to be called with ("c")`) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestStubConcurrentWait(t *testing.T) {
+	s := New()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func() { errs <- s.WaitForCalls(ctx, 50) }()
+	}
+	for i := 0; i < 50; i++ {
+		go s.Call()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}